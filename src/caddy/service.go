@@ -65,21 +65,30 @@ func (svc *CaddyService) CheckForCaddy(softFail bool) {
 	}
 }
 
-func (svc *CaddyService) GenerateDockerFiles() {
+// GenerateDockerFiles generates the proxy dockerfile and docker compose file and copies the web
+// clients, all the files are generated and the errors are returned together
+func (svc *CaddyService) GenerateDockerFiles() error {
 	notify.Wrench("Generating locally Docker Files")
 
-	svc.generateDockerfile()
+	errs := make([]error, 0)
+	if err := svc.generateDockerfile(); err != nil {
+		errs = append(errs, err)
+	}
 
 	for _, client := range config.GetCurrentContext().SpaServices {
 		// We only copy if the service is not a reverse proxy
 		if !client.UseReverseProxy {
 			if err := svc.copyWebClient(client); err != nil {
-				notify.Error(err.Error())
+				errs = append(errs, err)
 			}
 		}
 	}
 
-	svc.generateDockerComposeFile()
+	if err := svc.generateDockerComposeFile(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
 func (svc *CaddyService) GenerateCaddyFiles() error {
@@ -91,6 +100,7 @@ func (svc *CaddyService) GenerateCaddyFiles() error {
 		}
 	}
 
+	errs := make([]error, 0)
 	if config.GlobalConfiguration.Network != nil && config.GlobalConfiguration.Network.CERTPath != "" && config.GlobalConfiguration.Network.PrivateKeyPath != "" {
 		if !helper.FileExists(helper.JoinPath(config.GetCurrentContext().Configuration.OutputPath, common.CADDY_PATH, common.TLS_PATH)) {
 			notify.Hammer("Creating Caddy SSL Folder folder")
@@ -99,34 +109,36 @@ func (svc *CaddyService) GenerateCaddyFiles() error {
 			}
 		}
 
-		svc.copyCertificates()
+		if err := svc.copyCertificates(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if err := svc.generateMainCaddyFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if err := svc.generateBackendRootServicesAndRoutesCaddyFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if err := svc.generateHostedBackendServicesCaddyFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if err := svc.generateSpaServicesCaddyFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if err := svc.generateTenantsCaddyFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if err := svc.generateCaddyMockServicesRoutesFile(); err != nil {
-		notify.AddError(err.Error())
+		errs = append(errs, err)
 	}
 	if helper.GetFlagSwitch("update-dns", false) {
 		if err := hostSvc.GenerateHostsEntries(); err != nil {
-			notify.AddError(err.Error())
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func (svc *CaddyService) BuildContainer() error {
@@ -1,10 +1,13 @@
 package common
 
 import (
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cjlapao/common-go/helper"
 )
@@ -55,3 +58,28 @@ func EncodeName(name string) string {
 
 	return strings.ToLower(folderName)
 }
+
+// ParseDuration accepts a duration like 30s, 5m or 1h30m, a plain number is read as seconds
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("duration %s cannot be negative", value)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %s, use a duration like 30s, 5m or 1h30m", value)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration %s cannot be negative", value)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetJsonPathValue returns the value in a json document for a simple path, the path uses dots
+// to navigate objects and either brackets or dots for array indexes, for example
+// $.items[0].name or items.0.name
+func GetJsonPathValue(content string, path string) (interface{}, error) {
+	var document interface{}
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		return nil, err
+	}
+
+	current := document
+	for _, part := range splitJsonPath(path) {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[part]
+			if !ok {
+				return nil, fmt.Errorf("key %s was not found in path %s", part, path)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid array index %s in path %s", part, path)
+			}
+			if index < 0 {
+				index = len(value) + index
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("array index %s is out of range in path %s", part, path)
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("cannot find %s in path %s, value is not an object or array", part, path)
+		}
+	}
+
+	return current, nil
+}
+
+// GetJsonPathString returns the value of the path as a string, objects and arrays are returned
+// as their json representation
+func GetJsonPathString(content string, path string) (string, error) {
+	value, err := GetJsonPathValue(content, path)
+	if err != nil {
+		return "", err
+	}
+
+	return JsonValueToString(value), nil
+}
+
+func JsonValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
+
+func splitJsonPath(path string) []string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	result := make([]string, 0)
+	for _, part := range strings.Split(path, ".") {
+		part = strings.Trim(strings.TrimSpace(part), "'\"")
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
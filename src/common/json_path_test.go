@@ -0,0 +1,48 @@
+package common
+
+import "testing"
+
+func TestGetJsonPathString(t *testing.T) {
+	content := `{"id":"42","count":3,"enabled":true,"empty":"","missing":null,"tenant":{"name":"acme","tags":["a","b"]},"items":[{"name":"first"},{"name":"last"}]}`
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"root key", "$.id", "42", false},
+		{"without prefix", "id", "42", false},
+		{"number", "$.count", "3", false},
+		{"bool", "$.enabled", "true", false},
+		{"empty string", "$.empty", "", false},
+		{"null", "$.missing", "", false},
+		{"nested key", "$.tenant.name", "acme", false},
+		{"bracket index", "$.items[1].name", "last", false},
+		{"dot index", "items.0.name", "first", false},
+		{"negative index", "$.items[-1].name", "last", false},
+		{"quoted key", "$['tenant']['name']", "acme", false},
+		{"object", "$.tenant", `{"name":"acme","tags":["a","b"]}`, false},
+		{"array", "$.tenant.tags", `["a","b"]`, false},
+		{"missing key", "$.tenant.id", "", true},
+		{"index out of range", "$.items[2].name", "", true},
+		{"invalid index", "$.items[first]", "", true},
+		{"path through a value", "$.id.value", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetJsonPathString(content, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetJsonPathString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetJsonPathString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := GetJsonPathString("not json", "$.id"); err == nil {
+		t.Errorf("expected an error for an invalid json document")
+	}
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	shutdownContext, cancelShutdown = context.WithCancel(context.Background())
+	cancellableOperations           sync.WaitGroup
+)
+
+// ShutdownContext is cancelled when the user asks locally to stop (Ctrl+C or SIGTERM), long
+// running operations should derive their contexts from it
+func ShutdownContext() context.Context {
+	return shutdownContext
+}
+
+// StartCancellableOperation tells the shutdown handler that an operation is listening to the
+// ShutdownContext and should be given time to stop, the returned function needs to be called
+// once the operation is finished
+func StartCancellableOperation() func() {
+	cancellableOperations.Add(1)
+	return cancellableOperations.Done
+}
+
+// Shutdown cancels the ShutdownContext and waits for the cancellable operations to finish, it
+// returns false if they did not finish within the timeout
+func Shutdown(timeout time.Duration) bool {
+	cancelShutdown()
+
+	done := make(chan struct{})
+	go func() {
+		cancellableOperations.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
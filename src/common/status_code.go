@@ -0,0 +1,41 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseStatusCodes reads a list of accepted http status codes, a code can be an exact value like
+// 204 or a class like 2xx
+func ParseStatusCodes(values []string) ([]string, error) {
+	result := make([]string, 0)
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len(value) == 3 && strings.HasSuffix(value, "xx") && value[0] >= '1' && value[0] <= '5' {
+			result = append(result, value)
+			continue
+		}
+		if code, err := strconv.Atoi(value); err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status %s, use a status code like 200 or a class like 2xx", value)
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// MatchStatusCode returns true if the status code is one of the accepted codes or classes
+func MatchStatusCode(code int, accepted []string) bool {
+	value := strconv.Itoa(code)
+	for _, status := range accepted {
+		if status == value {
+			return true
+		}
+		if strings.HasSuffix(status, "xx") && status[0] == value[0] {
+			return true
+		}
+	}
+
+	return false
+}
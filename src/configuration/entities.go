@@ -16,6 +16,15 @@ type GlobalConfiguration struct {
 	Network              *Network                    `json:"network,omitempty" yaml:"network,omitempty"`
 	CertificateGenerator *CertificateGeneratorConfig `json:"certificateGenerator,omitempty" yaml:"certificateGenerator,omitempty"`
 	Cors                 *Cors                       `json:"cors,omitempty" yaml:"cors,omitempty"`
+	PipelineWorkers      []*PipelineWorkerConfig     `json:"pipelineWorkers,omitempty" yaml:"pipelineWorkers,omitempty"`
+}
+
+// PipelineWorkerConfig declares the executable of a plugin worker for a task type, plugin workers
+// that are not declared are found in the path as locally-worker-<type>
+type PipelineWorkerConfig struct {
+	Type      string   `json:"type" yaml:"type"`
+	Path      string   `json:"path" yaml:"path"`
+	Arguments []string `json:"arguments,omitempty" yaml:"arguments,omitempty"`
 }
 
 type Cors struct {
@@ -610,6 +610,11 @@ func (svc *ConfigService) loadContextConfigurationFragment(context *locally_cont
 		}
 		context.Pipelines = append(context.Pipelines, configFile.Pipelines...)
 
+		for _, template := range configFile.PipelineTemplates {
+			template.Source = configFile.Source
+		}
+		context.PipelineTemplates = append(context.PipelineTemplates, configFile.PipelineTemplates...)
+
 		for _, m := range configFile.SpaServices {
 			m.Source = configFile.Source
 		}
@@ -40,6 +40,7 @@ type Context struct {
 	NugetPackages        *nuget_package_component.NugetPackages                `json:"nugetPackages,omitempty" yaml:"nugetPackages,omitempty"`
 	Tenants              []*context_entities.Tenant                            `json:"tenants,omitempty" yaml:"tenants,omitempty"`
 	Pipelines            []*pipeline_component.Pipeline                        `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
+	PipelineTemplates    []*pipeline_component.PipelineTemplate                `json:"pipelineTemplates,omitempty" yaml:"pipelineTemplates,omitempty"`
 	Credentials          *context_entities.Credentials                         `json:"credentials,omitempty" yaml:"credentials,omitempty"`
 	BackendConfig        *infrastructure_component.InfrastructureBackendConfig `json:"backendConfig,omitempty" yaml:"backendConfig,omitempty"`
 	Fragments            []*Context                                            `json:"-" yaml:"-"`
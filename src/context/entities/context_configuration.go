@@ -14,6 +14,7 @@ type ContextConfiguration struct {
 	RootURI              string                         `json:"rootUri,omitempty" yaml:"rootUri,omitempty"`
 	OutputPath           string                         `json:"outputPath,omitempty" yaml:"outputPath,omitempty"`
 	LocallyConfigService *entities.LocallyConfigService `json:"locallyConfigService,omitempty" yaml:"locallyConfigService,omitempty"`
+	Vaults               *ContextVaults                 `json:"vaults,omitempty" yaml:"vaults,omitempty"`
 }
 
 type ContextLocation struct {
@@ -0,0 +1,57 @@
+package entities
+
+// ContextVaults holds the settings of the environment vaults that read their values from outside
+// of the context configuration
+type ContextVaults struct {
+	DotEnv    *DotEnvVault    `json:"dotenv,omitempty" yaml:"dotenv,omitempty"`
+	Os        *OsVault        `json:"os,omitempty" yaml:"os,omitempty"`
+	Secrets   *SecretsVault   `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	HashiCorp *HashiCorpVault `json:"hashicorp,omitempty" yaml:"hashicorp,omitempty"`
+}
+
+// DotEnvVault lists the .env files of the context, relative paths are relative to the context
+// configuration file and later files override the values of the previous ones
+type DotEnvVault struct {
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// OsVault limits the process environment variables exposed in the os vault to the ones starting
+// with the prefix
+type OsVault struct {
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// SecretsVault sets where the encrypted secrets of the context and the key used to encrypt them
+// are stored, by default the secrets are next to the context configuration file and the key in
+// the user configuration folder
+type SecretsVault struct {
+	File    string `json:"file,omitempty" yaml:"file,omitempty"`
+	KeyFile string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+}
+
+// HashiCorpVault reads the kv v2 secrets of a vault compatible server, the address, token and
+// namespace default to the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables
+type HashiCorpVault struct {
+	Address   string                 `json:"address,omitempty" yaml:"address,omitempty"`
+	Namespace string                 `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Mount     string                 `json:"mount,omitempty" yaml:"mount,omitempty"`
+	Token     string                 `json:"token,omitempty" yaml:"token,omitempty"`
+	AppRole   *HashiCorpVaultAppRole `json:"appRole,omitempty" yaml:"appRole,omitempty"`
+	Timeout   int                    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Paths     []HashiCorpVaultPath   `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// HashiCorpVaultAppRole logs in with an approle instead of a token, the secret id defaults to the
+// VAULT_SECRET_ID environment variable so it does not need to be in the configuration
+type HashiCorpVaultAppRole struct {
+	Mount    string `json:"mount,omitempty" yaml:"mount,omitempty"`
+	RoleId   string `json:"roleId,omitempty" yaml:"roleId,omitempty"`
+	SecretId string `json:"secretId,omitempty" yaml:"secretId,omitempty"`
+}
+
+// HashiCorpVaultPath maps the fields of a secret to keys starting with the prefix, paths ending
+// with / map all the secrets under them using their names after the prefix
+type HashiCorpVaultPath struct {
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
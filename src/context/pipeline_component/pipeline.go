@@ -6,11 +6,15 @@ import (
 
 type Pipeline struct {
 	Source     string
-	Disabled   bool           `json:"disabled" yaml:"disabled"`
-	Name       string         `json:"name,omitempty" yaml:"name,omitempty"`
-	Jobs       []*PipelineJob `json:"jobs,omitempty" yaml:"jobs,omitempty"`
-	DependsOn  []string       `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
-	RequiredBy []string       `json:"-" yaml:"-"`
+	Disabled   bool                 `json:"disabled" yaml:"disabled"`
+	Name       string               `json:"name,omitempty" yaml:"name,omitempty"`
+	If         string               `json:"if,omitempty" yaml:"if,omitempty"`
+	Parameters []*PipelineParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Jobs       []*PipelineJob       `json:"jobs,omitempty" yaml:"jobs,omitempty"`
+	OnFailure  []*PipelineJob       `json:"onFailure,omitempty" yaml:"onFailure,omitempty"`
+	Finally    []*PipelineJob       `json:"finally,omitempty" yaml:"finally,omitempty"`
+	DependsOn  []string             `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	RequiredBy []string             `json:"-" yaml:"-"`
 }
 
 func (pipeline *Pipeline) GetName() string {
@@ -6,11 +6,15 @@ import (
 
 type PipelineJob struct {
 	source     string
-	Disabled   bool            `json:"disabled" yaml:"disabled"`
-	Name       string          `json:"name,omitempty" yaml:"name,omitempty"`
-	Steps      []*PipelineTask `json:"steps,omitempty" yaml:"steps,omitempty"`
-	DependsOn  []string        `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
-	RequiredBy []string        `json:"-" yaml:"-"`
+	Disabled   bool                   `json:"disabled" yaml:"disabled"`
+	Name       string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	If         string                 `json:"if,omitempty" yaml:"if,omitempty"`
+	Timeout    string                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Template   string                 `json:"template,omitempty" yaml:"template,omitempty"`
+	With       map[string]interface{} `json:"with,omitempty" yaml:"with,omitempty"`
+	Steps      []*PipelineTask        `json:"steps,omitempty" yaml:"steps,omitempty"`
+	DependsOn  []string               `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	RequiredBy []string               `json:"-" yaml:"-"`
 }
 
 func (job *PipelineJob) GetName() string {
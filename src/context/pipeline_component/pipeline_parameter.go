@@ -0,0 +1,30 @@
+package pipeline_component
+
+const (
+	ParameterTypeString = "string"
+	ParameterTypeBool   = "bool"
+	ParameterTypeInt    = "int"
+	ParameterTypeChoice = "choice"
+)
+
+// PipelineParameter is a typed input of a pipeline or a template, parameters without a default
+// value are required
+type PipelineParameter struct {
+	Name        string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Type        string      `json:"type,omitempty" yaml:"type,omitempty"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	Values      []string    `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+func (parameter *PipelineParameter) GetType() string {
+	if parameter.Type == "" {
+		return ParameterTypeString
+	}
+
+	return parameter.Type
+}
+
+func (parameter *PipelineParameter) IsRequired() bool {
+	return parameter.Default == nil
+}
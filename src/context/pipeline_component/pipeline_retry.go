@@ -0,0 +1,17 @@
+package pipeline_component
+
+// PipelineRetry defines how many times and how often a task is retried when it fails
+type PipelineRetry struct {
+	MaxAttempts int              `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	Backoff     string           `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	Delay       string           `json:"delay,omitempty" yaml:"delay,omitempty"`
+	MaxDelay    string           `json:"maxDelay,omitempty" yaml:"maxDelay,omitempty"`
+	RetryOn     *PipelineRetryOn `json:"retryOn,omitempty" yaml:"retryOn,omitempty"`
+}
+
+// PipelineRetryOn limits the failures that are retried, when empty any failure is retried
+type PipelineRetryOn struct {
+	StatusCodes []int    `json:"statusCodes,omitempty" yaml:"statusCodes,omitempty"`
+	ExitCodes   []int    `json:"exitCodes,omitempty" yaml:"exitCodes,omitempty"`
+	Errors      []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
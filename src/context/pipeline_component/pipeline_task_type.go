@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +27,17 @@ const (
 	WhatsNewTask
 	NpmTask
 	WebClientManifestTask
+	WaitTask
+	TemplateTask
+)
+
+// customTaskTypesStart is the first value given to the task types that are not built in, like
+// the types handled by plugin workers
+const customTaskTypesStart PipelineTaskType = 1000
+
+var (
+	taskTypesMutex     sync.RWMutex
+	nextCustomTaskType = customTaskTypesStart
 )
 
 var toPipelineTaskTypeString = map[PipelineTaskType]string{
@@ -44,6 +56,8 @@ var toPipelineTaskTypeString = map[PipelineTaskType]string{
 	WebClientManifestTask: "webclientmanifest",
 	UnknownTask:           "unknown",
 	NpmTask:               "npm",
+	WaitTask:              "wait",
+	TemplateTask:          "template",
 }
 
 var toPipelineTaskType = map[string]PipelineTaskType{
@@ -62,15 +76,48 @@ var toPipelineTaskType = map[string]PipelineTaskType{
 	"webclientmanifest": WebClientManifestTask,
 	"unknown":           UnknownTask,
 	"npm":               NpmTask,
+	"wait":              WaitTask,
+	"template":          TemplateTask,
+}
+
+// GetPipelineTaskType returns the task type of a name, names that are not built in are registered
+// as custom task types so they can be handled by plugin workers
+func GetPipelineTaskType(name string) PipelineTaskType {
+	taskTypesMutex.RLock()
+	taskType, ok := toPipelineTaskType[name]
+	taskTypesMutex.RUnlock()
+	if ok || name == "" {
+		return taskType
+	}
+
+	taskTypesMutex.Lock()
+	defer taskTypesMutex.Unlock()
+	if taskType, ok := toPipelineTaskType[name]; ok {
+		return taskType
+	}
+
+	taskType = nextCustomTaskType
+	nextCustomTaskType += 1
+	toPipelineTaskType[name] = taskType
+	toPipelineTaskTypeString[taskType] = name
+	return taskType
 }
 
 func (t PipelineTaskType) String() string {
+	taskTypesMutex.RLock()
+	defer taskTypesMutex.RUnlock()
+
 	return toPipelineTaskTypeString[t]
 }
 
+// IsCustom returns true for the task types that are not built in
+func (t PipelineTaskType) IsCustom() bool {
+	return t >= customTaskTypesStart
+}
+
 func (t PipelineTaskType) MarshalJSON() ([]byte, error) {
 	buffer := bytes.NewBufferString(`"`)
-	buffer.WriteString(toPipelineTaskTypeString[t])
+	buffer.WriteString(t.String())
 	buffer.WriteString(`"`)
 
 	return buffer.Bytes(), nil
@@ -83,18 +130,18 @@ func (t *PipelineTaskType) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	*t = toPipelineTaskType[key]
+	*t = GetPipelineTaskType(key)
 	return nil
 }
 
 func (t PipelineTaskType) MarshalYAML() (interface{}, error) {
-	return toPipelineTaskTypeString[t], nil
+	return t.String(), nil
 }
 
 func (t *PipelineTaskType) UnmarshalYAML(value *yaml.Node) error {
 	switch value.Kind {
 	case yaml.Kind(yaml.LiteralStyle):
-		*t = toPipelineTaskType[value.Value]
+		*t = GetPipelineTaskType(value.Value)
 	default:
 		return errors.New("invalid format for the current enum type")
 	}
@@ -8,11 +8,17 @@ type PipelineTask struct {
 	source              string
 	Disabled            bool                   `json:"disabled" yaml:"disabled"`
 	Name                string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	If                  string                 `json:"if,omitempty" yaml:"if,omitempty"`
 	Type                PipelineTaskType       `json:"type,omitempty" yaml:"type,omitempty"`
+	Template            string                 `json:"template,omitempty" yaml:"template,omitempty"`
+	With                map[string]interface{} `json:"with,omitempty" yaml:"with,omitempty"`
 	RetryCountOnFailure int                    `json:"retryCountOnFailure,omitempty" yaml:"retryCountOnFailure,omitempty"`
+	Timeout             string                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry               *PipelineRetry         `json:"retry,omitempty" yaml:"retry,omitempty"`
 	WorkingDirectory    string                 `json:"workingDirectory,omitempty" yaml:"workingDirectory,omitempty"`
 	Inputs              map[string]interface{} `json:"inputs,omitempty" yaml:"inputs,omitempty"`
 	Body                string                 `json:"body,omitempty" yaml:"body,omitempty"`
+	Outputs             map[string]string      `json:"outputs,omitempty" yaml:"outputs,omitempty"`
 	DependsOn           []string               `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
 	RequiredBy          []string               `json:"-" yaml:"-"`
 }
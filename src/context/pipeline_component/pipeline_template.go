@@ -0,0 +1,11 @@
+package pipeline_component
+
+// PipelineTemplate is a parameterised set of steps that can be reused by any pipeline, either as
+// a whole job or as steps of an existing job, using the template field with the parameter values
+// in the with field
+type PipelineTemplate struct {
+	Source     string               `json:"-" yaml:"-"`
+	Name       string               `json:"name,omitempty" yaml:"name,omitempty"`
+	Parameters []*PipelineParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Steps      []*PipelineTask      `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
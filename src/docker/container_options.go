@@ -0,0 +1,195 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImageBuildOptions builds an image from a dockerfile, the build arguments are passed to docker
+// as environment variables so their values are not part of the command line
+type ImageBuildOptions struct {
+	Context     string
+	Dockerfile  string
+	Target      string
+	BuildArgs   map[string]string
+	Tags        []string
+	NoCache     bool
+	Platform    string
+	ImageIdFile string
+}
+
+func (o ImageBuildOptions) GetArguments() ([]string, []string, error) {
+	if len(o.Tags) == 0 {
+		return nil, nil, errors.New("at least one tag is required to build an image")
+	}
+
+	args := []string{"build"}
+	for _, tag := range o.Tags {
+		args = append(args, "-t", tag)
+	}
+	if o.Dockerfile != "" {
+		args = append(args, "-f", o.Dockerfile)
+	}
+	if o.Target != "" {
+		args = append(args, "--target", o.Target)
+	}
+	if o.Platform != "" {
+		args = append(args, "--platform", o.Platform)
+	}
+	if o.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if o.ImageIdFile != "" {
+		args = append(args, "--iidfile", o.ImageIdFile)
+	}
+
+	env := make([]string, 0)
+	for _, key := range sortedKeys(o.BuildArgs) {
+		args = append(args, "--build-arg", key)
+		env = append(env, fmt.Sprintf("%s=%s", key, o.BuildArgs[key]))
+	}
+
+	context := o.Context
+	if context == "" {
+		context = "."
+	}
+	args = append(args, context)
+
+	return args, env, nil
+}
+
+// ContainerRunOptions runs a one off container, the environment variables are passed to docker
+// as environment variables so their values are not part of the command line
+type ContainerRunOptions struct {
+	Image      string
+	Name       string
+	Entrypoint string
+	Command    []string
+	Env        map[string]string
+	Mounts     []string
+	Network    string
+	WorkingDir string
+	User       string
+	Keep       bool
+}
+
+func (o ContainerRunOptions) GetArguments() ([]string, []string, error) {
+	if o.Image == "" {
+		return nil, nil, errors.New("image cannot be empty")
+	}
+
+	args := []string{"run"}
+	if !o.Keep {
+		args = append(args, "--rm")
+	}
+	if o.Name != "" {
+		args = append(args, "--name", o.Name)
+	}
+	if o.Network != "" {
+		args = append(args, "--network", o.Network)
+	}
+	if o.WorkingDir != "" {
+		args = append(args, "-w", o.WorkingDir)
+	}
+	if o.User != "" {
+		args = append(args, "-u", o.User)
+	}
+	if o.Entrypoint != "" {
+		args = append(args, "--entrypoint", o.Entrypoint)
+	}
+	for _, mount := range o.Mounts {
+		volume, err := absoluteMount(mount)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, "-v", volume)
+	}
+
+	args, env := appendEnvironment(args, o.Env)
+	args = append(args, o.Image)
+	args = append(args, o.Command...)
+
+	return args, env, nil
+}
+
+// ContainerExecOptions runs a command in a running container
+type ContainerExecOptions struct {
+	Container  string
+	Command    []string
+	Env        map[string]string
+	WorkingDir string
+	User       string
+}
+
+func (o ContainerExecOptions) GetArguments() ([]string, []string, error) {
+	if o.Container == "" {
+		return nil, nil, errors.New("container cannot be empty")
+	}
+	if len(o.Command) == 0 {
+		return nil, nil, errors.New("command cannot be empty")
+	}
+
+	args := []string{"exec"}
+	if o.WorkingDir != "" {
+		args = append(args, "-w", o.WorkingDir)
+	}
+	if o.User != "" {
+		args = append(args, "-u", o.User)
+	}
+
+	args, env := appendEnvironment(args, o.Env)
+	args = append(args, o.Container)
+	args = append(args, o.Command...)
+
+	return args, env, nil
+}
+
+func appendEnvironment(args []string, variables map[string]string) ([]string, []string) {
+	env := make([]string, 0)
+	for _, key := range sortedKeys(variables) {
+		args = append(args, "-e", key)
+		env = append(env, fmt.Sprintf("%s=%s", key, variables[key]))
+	}
+
+	return args, env
+}
+
+// absoluteMount makes the relative host path of a bind mount absolute as docker does not accept
+// relative paths, named volumes are kept as they are
+func absoluteMount(mount string) (string, error) {
+	// windows paths start with a drive letter that should not be taken as the separator
+	drive := ""
+	if len(mount) > 2 && mount[1] == ':' && (mount[2] == '\\' || mount[2] == '/') {
+		drive = mount[:2]
+		mount = mount[2:]
+	}
+
+	parts := strings.SplitN(mount, ":", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid mount %s%s, it needs to be source:target", drive, mount)
+	}
+
+	source := drive + parts[0]
+	if strings.HasPrefix(source, ".") {
+		path, err := filepath.Abs(source)
+		if err != nil {
+			return "", err
+		}
+		source = path
+	}
+
+	return fmt.Sprintf("%s:%s", source, parts[1]), nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
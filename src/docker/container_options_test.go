@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainerRunOptions_GetArguments(t *testing.T) {
+	options := ContainerRunOptions{
+		Image:   "postgres:16",
+		Command: []string{"psql", "-f", "/seed/seed.sql"},
+		Env:     map[string]string{"PGPASSWORD": "secret", "PGHOST": "db"},
+		Mounts:  []string{"./seed:/seed:ro", "data:/var/lib/data"},
+		Network: "locally",
+	}
+
+	args, env, err := options.GetArguments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, _ := filepath.Abs("./seed")
+	want := []string{"run", "--rm", "--network", "locally", "-v", seed + ":/seed:ro", "-v", "data:/var/lib/data", "-e", "PGHOST", "-e", "PGPASSWORD", "postgres:16", "psql", "-f", "/seed/seed.sql"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("GetArguments() = %q, want %q", args, want)
+	}
+	if strings.Contains(strings.Join(args, " "), "secret") {
+		t.Errorf("expected the environment values not to be in the arguments")
+	}
+	if !reflect.DeepEqual(env, []string{"PGHOST=db", "PGPASSWORD=secret"}) {
+		t.Errorf("unexpected environment %q", env)
+	}
+
+	if _, _, err := (ContainerRunOptions{Image: "alpine", Mounts: []string{"/only-source"}}).GetArguments(); err == nil {
+		t.Errorf("expected an invalid mount to fail")
+	}
+}
+
+func TestImageBuildOptions_GetArguments(t *testing.T) {
+	args, env, err := ImageBuildOptions{
+		Context:    "src",
+		Dockerfile: "src/Dockerfile",
+		Target:     "runtime",
+		BuildArgs:  map[string]string{"VERSION": "1.0.0"},
+		Tags:       []string{"api:1.0.0", "api:latest"},
+	}.GetArguments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"build", "-t", "api:1.0.0", "-t", "api:latest", "-f", "src/Dockerfile", "--target", "runtime", "--build-arg", "VERSION", "src"}
+	if !reflect.DeepEqual(args, want) || !reflect.DeepEqual(env, []string{"VERSION=1.0.0"}) {
+		t.Errorf("GetArguments() = %q %q", args, env)
+	}
+
+	if _, _, err := (ImageBuildOptions{}).GetArguments(); err == nil {
+		t.Errorf("expected a build without tags to fail")
+	}
+}
@@ -55,6 +55,7 @@ type DockerServiceOptions struct {
 	DockerRegistry    *docker_component.DockerRegistry
 	DockerCompose     *docker_component.DockerCompose
 	StdOutput         bool
+	Strict            bool
 }
 
 func New() *DockerService {
@@ -678,6 +679,27 @@ func (svc *DockerService) ServiceContainerLogs(options *DockerServiceOptions) er
 	return nil
 }
 
+// GetServiceContainerId returns the id of the running container of a service, the component name
+// is the docker compose service of the service
+func (svc *DockerService) GetServiceContainerId(ctx context.Context, options *DockerServiceOptions) (string, error) {
+	currentContext := config.GetCurrentContext()
+	if currentContext == nil {
+		return "", errors.New("there is no context to find the service in")
+	}
+
+	for _, container := range currentContext.GetDockerServices(options.Name, true) {
+		containerPath, pathError := svc.getPath(container, options)
+		if pathError != nil {
+			return "", pathError
+		}
+		notify.Debug("Using Path: %s", containerPath)
+
+		return GetWrapper().GetComposeContainerId(ctx, containerPath, options.ComponentName)
+	}
+
+	return "", fmt.Errorf("service %v was not found in the configuration file", options.Name)
+}
+
 func (svc *DockerService) GenerateServiceDockerComposeOverrideFile(options *DockerServiceOptions) error {
 	serviceFound := false
 	ctx := config.GetCurrentContext()
@@ -699,6 +721,11 @@ func (svc *DockerService) GenerateServiceDockerComposeOverrideFile(options *Dock
 		}
 	}
 
+	// the files are only written once all of them are generated so a strict generation does not
+	// leave some of the services with new files and others with the old ones
+	checker := environment.Get().NewReferenceChecker()
+	files := make(map[string]string)
+	paths := make([]string, 0)
 	for _, container := range containers {
 		containerPath, pathError := svc.getPath(container, options)
 		if pathError != nil {
@@ -713,18 +740,39 @@ func (svc *DockerService) GenerateServiceDockerComposeOverrideFile(options *Dock
 		dockerComposeFile += "services:\n"
 		if len(container.Components) > 0 {
 			for _, component := range container.Components {
-				dockerComposeFile += svc.generateDockerComposeServiceOverride(component)
+				source := component.Source
+				if source == "" {
+					source = container.Source
+				}
+				fragment := svc.generateDockerComposeServiceOverride(component)
+				checker.CheckReplaced(source, fmt.Sprintf("%s.%s", container.Name, component.Name), fragment)
+				dockerComposeFile += fragment
 			}
 		} else {
-			dockerComposeFile += svc.generateDockerComposeServiceOverride(container)
+			fragment := svc.generateDockerComposeServiceOverride(container)
+			checker.CheckReplaced(container.Source, container.Name, fragment)
+			dockerComposeFile += fragment
 		}
 
-		if err := helper.WriteToFile(dockerComposeFile, filePath); err != nil {
-			return err
+		if _, ok := files[filePath]; !ok {
+			paths = append(paths, filePath)
 		}
+		files[filePath] = dockerComposeFile
 		serviceFound = true
 	}
 
+	if options.Strict {
+		if err := checker.Err(); err != nil {
+			return err
+		}
+	}
+
+	for _, filePath := range paths {
+		if err := helper.WriteToFile(files[filePath], filePath); err != nil {
+			return err
+		}
+	}
+
 	if !serviceFound {
 		return fmt.Errorf("service %v was not found in the configuration file", options.Name)
 	}
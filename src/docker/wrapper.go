@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -396,6 +397,120 @@ func (svc *DockerCommandWrapper) IsRunning(imageName string) (bool, error) {
 	return false, err
 }
 
+// GetContainerHealth returns the health status of a container, containers without a health check
+// return their state instead, for example running or exited
+func (svc *DockerCommandWrapper) GetContainerHealth(ctx context.Context, containerName string) (string, error) {
+	if containerName == "" {
+		return "", errors.New("container name cannot be empty or nil")
+	}
+
+	args := make([]string, 0)
+	args = append(args, "inspect")
+	args = append(args, "--format")
+	args = append(args, "{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}")
+	args = append(args, containerName)
+
+	output, err := executer.ExecuteWithNoOutputContext(ctx, helpers.GetDockerPath(), args...)
+	if err != nil {
+		if output.StdErr != "" {
+			return "", fmt.Errorf("%s", strings.TrimSpace(output.StdErr))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(output.StdOut), nil
+}
+
+// BuildImageContext builds an image with docker build showing its output as it runs
+func (svc *DockerCommandWrapper) BuildImageContext(ctx context.Context, options ImageBuildOptions) (executer.ExecuteOutput, error) {
+	args, env, err := options.GetArguments()
+	if err != nil {
+		return executer.ExecuteOutput{}, err
+	}
+
+	notify.Rocket("Building image %v", strings.Join(options.Tags, ", "))
+	notify.Debug("Build arguments: %s", fmt.Sprintf("%v", args))
+	output, err := executer.ExecuteAndWatchWithEnvContext(ctx, "", env, helpers.GetDockerPath(), args...)
+	svc.Output = output.StdOut
+
+	return output, err
+}
+
+// PushImageContext pushes an image to its registry, the registry login needs to be done before
+func (svc *DockerCommandWrapper) PushImageContext(ctx context.Context, image string) (executer.ExecuteOutput, error) {
+	if image == "" {
+		return executer.ExecuteOutput{}, errors.New("image cannot be empty")
+	}
+
+	notify.Rocket("Pushing image %v", image)
+	output, err := executer.ExecuteAndWatchContext(ctx, "", helpers.GetDockerPath(), "push", image)
+	svc.Output = output.StdOut
+
+	return output, err
+}
+
+// RunContainerContext runs a one off container and returns its output and exit code
+func (svc *DockerCommandWrapper) RunContainerContext(ctx context.Context, options ContainerRunOptions) (executer.ExecuteOutput, error) {
+	args, env, err := options.GetArguments()
+	if err != nil {
+		return executer.ExecuteOutput{}, err
+	}
+
+	notify.Rocket("Running container from image %v", options.Image)
+	notify.Debug("Run arguments: %s", fmt.Sprintf("%v", args))
+	output, err := executer.ExecuteAndWatchWithEnvContext(ctx, "", env, helpers.GetDockerPath(), args...)
+	svc.Output = output.StdOut
+
+	return output, err
+}
+
+// ExecContainerContext runs a command in a running container and returns its output and exit code
+func (svc *DockerCommandWrapper) ExecContainerContext(ctx context.Context, options ContainerExecOptions) (executer.ExecuteOutput, error) {
+	args, env, err := options.GetArguments()
+	if err != nil {
+		return executer.ExecuteOutput{}, err
+	}
+
+	notify.Rocket("Running %v in container %v", strings.Join(options.Command, " "), options.Container)
+	notify.Debug("Exec arguments: %s", fmt.Sprintf("%v", args))
+	output, err := executer.ExecuteAndWatchWithEnvContext(ctx, "", env, helpers.GetDockerPath(), args...)
+	svc.Output = output.StdOut
+
+	return output, err
+}
+
+// GetComposeContainerId returns the id of the running container of a docker compose project, the
+// component is the docker compose service and if empty the first container is returned
+func (svc *DockerCommandWrapper) GetComposeContainerId(ctx context.Context, path string, componentName string) (string, error) {
+	if path == "" {
+		return "", errors.New("path cannot be empty or nil")
+	}
+
+	args := make([]string, 0)
+	args = append(args, "--project-directory")
+	args = append(args, path)
+	args = append(args, "ps")
+	args = append(args, "-q")
+	if componentName != "" {
+		args = append(args, componentName)
+	}
+
+	output, err := executer.ExecuteWithNoOutputContext(ctx, helpers.GetDockerComposePath(), args...)
+	if err != nil {
+		if output.StdErr != "" {
+			return "", fmt.Errorf("%s", strings.TrimSpace(output.StdErr))
+		}
+		return "", err
+	}
+
+	ids := strings.Fields(output.StdOut)
+	if len(ids) == 0 {
+		return "", fmt.Errorf("there is no running container in %s", path)
+	}
+
+	return ids[0], nil
+}
+
 func (svc *DockerCommandWrapper) List(serviceName string) error {
 	env := environment.Get()
 
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/environment/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+var notify = notifications.Get()
+
+// JsonFunction returns the value of a json path in the value, ${{ steps.login.output | json $.token }}
+type JsonFunction struct {
+	name string
+}
+
+func (function JsonFunction) New() interfaces.VariableFunction {
+	return JsonFunction{
+		name: "json",
+	}
+}
+
+func (function JsonFunction) Name() string {
+	return function.name
+}
+
+func (function JsonFunction) Exec(value string, args ...string) (string, error) {
+	if len(args) == 0 {
+		notify.Warning("json needs the path of the value to return")
+		return value, nil
+	}
+
+	result, err := common.GetJsonPathString(value, args[0])
+	if err != nil {
+		return "", fmt.Errorf("path %s was not found in the json value, %s", args[0], err.Error())
+	}
+
+	return result, nil
+}
+
+// FileFunction returns the content of the file in its argument, or in the value if it has no
+// arguments, without the trailing line break, ${{ file ./certs/ca.pem }}
+type FileFunction struct {
+	name string
+}
+
+func (function FileFunction) New() interfaces.VariableFunction {
+	return FileFunction{
+		name: "file",
+	}
+}
+
+func (function FileFunction) Name() string {
+	return function.name
+}
+
+func (function FileFunction) Exec(value string, args ...string) (string, error) {
+	path := value
+	if len(args) > 0 {
+		path = strings.Join(args, " ")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("there was an error reading file %s, %s", path, err.Error())
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EnvFunction returns the operating system environment variable in its argument, or in the value
+// if it has no arguments, ${{ env HOME }}
+type EnvFunction struct {
+	name string
+}
+
+func (function EnvFunction) New() interfaces.VariableFunction {
+	return EnvFunction{
+		name: "env",
+	}
+}
+
+func (function EnvFunction) Name() string {
+	return function.name
+}
+
+func (function EnvFunction) Exec(value string, args ...string) (string, error) {
+	name := value
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return os.Getenv(name), nil
+}
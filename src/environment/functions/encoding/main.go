@@ -0,0 +1,96 @@
+package encoding
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/cjlapao/locally-cli/environment/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+var notify = notifications.Get()
+
+// Base64EncodeFunction returns the value base64 encoded
+type Base64EncodeFunction struct {
+	name string
+}
+
+func (function Base64EncodeFunction) New() interfaces.VariableFunction {
+	return Base64EncodeFunction{
+		name: "base64encode",
+	}
+}
+
+func (function Base64EncodeFunction) Name() string {
+	return function.name
+}
+
+func (function Base64EncodeFunction) Exec(value string, args ...string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(value)), nil
+}
+
+// Base64DecodeFunction returns the value base64 decoded, values that are not base64 are kept
+type Base64DecodeFunction struct {
+	name string
+}
+
+func (function Base64DecodeFunction) New() interfaces.VariableFunction {
+	return Base64DecodeFunction{
+		name: "base64decode",
+	}
+}
+
+func (function Base64DecodeFunction) Name() string {
+	return function.name
+}
+
+func (function Base64DecodeFunction) Exec(value string, args ...string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		notify.Warning("Value is not a valid base64 encoded, using the raw value")
+		return value, nil
+	}
+
+	return string(decoded), nil
+}
+
+// UrlEncodeFunction returns the value escaped to be used in a query string
+type UrlEncodeFunction struct {
+	name string
+}
+
+func (function UrlEncodeFunction) New() interfaces.VariableFunction {
+	return UrlEncodeFunction{
+		name: "urlencode",
+	}
+}
+
+func (function UrlEncodeFunction) Name() string {
+	return function.name
+}
+
+func (function UrlEncodeFunction) Exec(value string, args ...string) (string, error) {
+	return url.QueryEscape(value), nil
+}
+
+// Sha256Function returns the hex encoded sha256 hash of the value
+type Sha256Function struct {
+	name string
+}
+
+func (function Sha256Function) New() interfaces.VariableFunction {
+	return Sha256Function{
+		name: "sha256",
+	}
+}
+
+func (function Sha256Function) Name() string {
+	return function.name
+}
+
+func (function Sha256Function) Exec(value string, args ...string) (string, error) {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:]), nil
+}
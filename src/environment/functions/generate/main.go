@@ -0,0 +1,60 @@
+package generate
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cjlapao/locally-cli/environment/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// UuidFunction returns a new random uuid, ${{ uuid }}
+type UuidFunction struct {
+	name string
+}
+
+func (function UuidFunction) New() interfaces.VariableFunction {
+	return UuidFunction{
+		name: "uuid",
+	}
+}
+
+func (function UuidFunction) Name() string {
+	return function.name
+}
+
+func (function UuidFunction) Exec(value string, args ...string) (string, error) {
+	return uuid.NewString(), nil
+}
+
+// NowFunction returns the current utc time with the go layout in its argument, rfc3339 by default,
+// the layout can also be unix for the seconds since epoch, ${{ now 2006-01-02 }}
+type NowFunction struct {
+	name string
+}
+
+func (function NowFunction) New() interfaces.VariableFunction {
+	return NowFunction{
+		name: "now",
+	}
+}
+
+func (function NowFunction) Name() string {
+	return function.name
+}
+
+func (function NowFunction) Exec(value string, args ...string) (string, error) {
+	now := time.Now().UTC()
+	layout := strings.Join(args, " ")
+
+	switch strings.ToLower(layout) {
+	case "", "rfc3339":
+		return now.Format(time.RFC3339), nil
+	case "unix":
+		return strconv.FormatInt(now.Unix(), 10), nil
+	default:
+		return now.Format(layout), nil
+	}
+}
@@ -16,13 +16,15 @@ const (
 	ErrorInvalidConnection = "501"
 )
 
+// RandomValueFunction appends a random string with the length in its argument to the value,
+// ${{ global.name | random 5 }} or ${{ random 10 }}
 type RandomValueFunction struct {
 	name string
 }
 
 func (worker RandomValueFunction) New() interfaces.VariableFunction {
 	return RandomValueFunction{
-		name: "random.func",
+		name: "random",
 	}
 }
 
@@ -30,24 +32,19 @@ func (worker RandomValueFunction) Name() string {
 	return worker.name
 }
 
-func (worker RandomValueFunction) Exec(value string, args ...string) string {
-	if value == "" {
-		return value
+func (worker RandomValueFunction) Exec(value string, args ...string) (string, error) {
+	if len(args) == 0 {
+		notify.Warning("random needs the length of the value to generate")
+		return value, nil
 	}
 
-	if len(args) <= 1 {
-		return value
+	notify.Debug("Executing Random Function")
+	length, err := strconv.Atoi(args[0])
+	if err != nil {
+		notify.Warning("Length %s of the random value is not a number", args[0])
+		return value, nil
 	}
 
-	if args[0] == "random" {
-		notify.Debug("Executing Random Function")
-		length, err := strconv.Atoi(args[1])
-		if err != nil {
-			return value
-		}
-		r := cryptorand.GetRandomString(length)
-		return fmt.Sprintf("%s%s", value, r)
-	}
-
-	return value
+	r := cryptorand.GetRandomString(length)
+	return fmt.Sprintf("%s%s", value, r), nil
 }
@@ -0,0 +1,123 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/cjlapao/locally-cli/environment/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+var notify = notifications.Get()
+
+// DefaultFunction returns its arguments when the value is empty or was not found, ${{ global.port | default 8080 }}
+type DefaultFunction struct {
+	name string
+}
+
+func (function DefaultFunction) New() interfaces.VariableFunction {
+	return DefaultFunction{
+		name: "default",
+	}
+}
+
+func (function DefaultFunction) Name() string {
+	return function.name
+}
+
+func (function DefaultFunction) Exec(value string, args ...string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// UpperFunction returns the value in upper case
+type UpperFunction struct {
+	name string
+}
+
+func (function UpperFunction) New() interfaces.VariableFunction {
+	return UpperFunction{
+		name: "upper",
+	}
+}
+
+func (function UpperFunction) Name() string {
+	return function.name
+}
+
+func (function UpperFunction) Exec(value string, args ...string) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+// LowerFunction returns the value in lower case
+type LowerFunction struct {
+	name string
+}
+
+func (function LowerFunction) New() interfaces.VariableFunction {
+	return LowerFunction{
+		name: "lower",
+	}
+}
+
+func (function LowerFunction) Name() string {
+	return function.name
+}
+
+func (function LowerFunction) Exec(value string, args ...string) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+// TrimFunction removes the whitespace around the value, or the characters in its argument
+type TrimFunction struct {
+	name string
+}
+
+func (function TrimFunction) New() interfaces.VariableFunction {
+	return TrimFunction{
+		name: "trim",
+	}
+}
+
+func (function TrimFunction) Name() string {
+	return function.name
+}
+
+func (function TrimFunction) Exec(value string, args ...string) (string, error) {
+	if len(args) == 0 {
+		return strings.TrimSpace(value), nil
+	}
+
+	return strings.Trim(value, strings.Join(args, "")), nil
+}
+
+// ReplaceFunction replaces all the occurrences of its first argument with the second one
+type ReplaceFunction struct {
+	name string
+}
+
+func (function ReplaceFunction) New() interfaces.VariableFunction {
+	return ReplaceFunction{
+		name: "replace",
+	}
+}
+
+func (function ReplaceFunction) Name() string {
+	return function.name
+}
+
+func (function ReplaceFunction) Exec(value string, args ...string) (string, error) {
+	if len(args) == 0 || args[0] == "" {
+		notify.Warning("replace needs the text to replace and its replacement")
+		return value, nil
+	}
+
+	replacement := ""
+	if len(args) > 1 {
+		replacement = args[1]
+	}
+
+	return strings.ReplaceAll(value, args[0], replacement), nil
+}
@@ -1,7 +1,9 @@
 package interfaces
 
+// VariableFunction transforms the value of a placeholder, an error keeps the placeholder as it
+// is so it is reported as unresolved
 type VariableFunction interface {
 	Name() string
 	New() VariableFunction
-	Exec(value string, args ...string) string
+	Exec(value string, args ...string) (string, error)
 }
@@ -0,0 +1,81 @@
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const MASK = "******"
+
+// vaults holding values that should never be printed
+var secretVaults = []string{"keyvault", "credentials", "secrets", "hashicorp"}
+
+// vaults holding values from outside of the context where only the keys that look like secrets
+// are masked
+var secretKeyVaults = []string{"dotenv", "os"}
+
+// keys that usually hold secrets, used to mask values by their name
+var secretKeyNames = []string{"password", "secret", "token", "authorization", "apikey", "api_key", "connectionstring"}
+
+var secretPairsRegex = regexp.MustCompile(`(?i)((?:password|pwd|secret|token)\s*=\s*)([^;&\s]+)`)
+
+// Mask replaces any value coming from a secret vault and any password like pair (for example
+// in a connection string) with a mask, it is meant to be used before printing resolved values
+func (env *Environment) Mask(source string) string {
+	if source == "" {
+		return source
+	}
+
+	secrets := env.secretValues()
+	for _, secret := range secrets {
+		source = strings.ReplaceAll(source, secret, MASK)
+	}
+
+	return secretPairsRegex.ReplaceAllString(source, "${1}"+MASK)
+}
+
+// IsSecretKey checks if a key name looks like it holds a secret, like password or clientSecret
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(strings.ReplaceAll(key, "-", ""))
+	for _, name := range secretKeyNames {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (env *Environment) secretValues() []string {
+	env.mutex.RLock()
+	defer env.mutex.RUnlock()
+
+	result := make([]string, 0)
+	for _, vault := range secretVaults {
+		for _, value := range env.variables[vault] {
+			secret := fmt.Sprintf("%v", value)
+			// very short values would mask unrelated text
+			if len(secret) >= 4 {
+				result = append(result, secret)
+			}
+		}
+	}
+
+	for _, vault := range secretKeyVaults {
+		for key, value := range env.variables[vault] {
+			secret := fmt.Sprintf("%v", value)
+			if IsSecretKey(key) && len(secret) >= 4 {
+				result = append(result, secret)
+			}
+		}
+	}
+
+	// longer secrets first so a secret containing another one is fully masked
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) > len(result[j])
+	})
+
+	return result
+}
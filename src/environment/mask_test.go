@@ -0,0 +1,58 @@
+package environment
+
+import "testing"
+
+func TestEnvironment_Mask(t *testing.T) {
+	env := &Environment{
+		variables: map[string]map[string]interface{}{
+			"keyvault":  {"db_password": "S3cr3tValue"},
+			"global":    {"name": "S3cr3t"},
+			"dotenv":    {"api_token": "D0tEnvToken", "api_url": "https://api.local"},
+			"secrets":   {"license": "L1censeKey"},
+			"hashicorp": {"api.password": "V4ultValue"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"keyvault value", "login with S3cr3tValue", "login with " + MASK},
+		{"secrets value", "license L1censeKey", "license " + MASK},
+		{"hashicorp value", "login with V4ultValue", "login with " + MASK},
+		{"other vaults are not masked", "name is S3cr3t", "name is S3cr3t"},
+		{"dotenv secret key", "using D0tEnvToken on https://api.local", "using " + MASK + " on https://api.local"},
+		{"connection string password", "Server=db;User Id=sa;Password=abc123;", "Server=db;User Id=sa;Password=" + MASK + ";"},
+		{"query string token", "https://host/api?token=abc&page=1", "https://host/api?token=" + MASK + "&page=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := env.Mask(tt.source); got != tt.want {
+				t.Errorf("Mask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"password", true},
+		{"clientSecret", true},
+		{"Authorization", true},
+		{"connectionString", true},
+		{"host", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSecretKey(tt.key); got != tt.want {
+				t.Errorf("IsSecretKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
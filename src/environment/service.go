@@ -2,18 +2,28 @@ package environment
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/cjlapao/locally-cli/environment/functions/data"
+	"github.com/cjlapao/locally-cli/environment/functions/encoding"
+	"github.com/cjlapao/locally-cli/environment/functions/generate"
 	"github.com/cjlapao/locally-cli/environment/functions/random"
+	"github.com/cjlapao/locally-cli/environment/functions/text"
 	env_interfaces "github.com/cjlapao/locally-cli/environment/interfaces"
 	"github.com/cjlapao/locally-cli/interfaces"
 	"github.com/cjlapao/locally-cli/tools"
 	"github.com/cjlapao/locally-cli/vaults/backend_vault"
 	"github.com/cjlapao/locally-cli/vaults/config_vault"
 	"github.com/cjlapao/locally-cli/vaults/credentials_vault"
+	"github.com/cjlapao/locally-cli/vaults/dotenv_vault"
+	"github.com/cjlapao/locally-cli/vaults/os_vault"
+	"github.com/cjlapao/locally-cli/vaults/secrets_vault"
 
 	"github.com/cjlapao/locally-cli/vaults/global_vault"
+	"github.com/cjlapao/locally-cli/vaults/hashicorp_vault"
 	"github.com/cjlapao/locally-cli/vaults/keyvault_vault"
 	"github.com/cjlapao/locally-cli/vaults/terraform_vault"
 
@@ -22,27 +32,30 @@ import (
 
 var globalEnvironment *Environment
 
+var placeholderRegex = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
 const (
 	PREFIX string = "${{"
 	SUFFIX string = "}}"
 )
 
 type Environment struct {
+	mutex         sync.RWMutex
 	isInitialized bool
 	variables     map[string]map[string]interface{}
 	vaults        []interfaces.EnvironmentVault
 	isSync        bool
-	functions     []env_interfaces.VariableFunction
+	functions     map[string]env_interfaces.VariableFunction
 }
 
 func New() *Environment {
 	svc := Environment{
 		variables:     make(map[string]map[string]interface{}),
 		vaults:        make([]interfaces.EnvironmentVault, 0),
-		functions:     make([]env_interfaces.VariableFunction, 0),
 		isSync:        false,
 		isInitialized: false,
 	}
+	svc.registerFunctions()
 
 	// Adding system environment variables
 	svc.addEnvironment("API_PREFIX", "api")
@@ -68,9 +81,6 @@ func (env *Environment) Initialize() {
 	if len(env.vaults) > 0 {
 		env.vaults = make([]interfaces.EnvironmentVault, 0)
 	}
-	if len(env.functions) > 0 {
-		env.functions = make([]env_interfaces.VariableFunction, 0)
-	}
 
 	// Adding environment vaults
 	env.vaults = append(env.vaults, config_vault.New())
@@ -79,9 +89,13 @@ func (env *Environment) Initialize() {
 	env.vaults = append(env.vaults, global_vault.New())
 	env.vaults = append(env.vaults, terraform_vault.New())
 	env.vaults = append(env.vaults, keyvault_vault.New())
+	env.vaults = append(env.vaults, dotenv_vault.New())
+	env.vaults = append(env.vaults, os_vault.New())
+	env.vaults = append(env.vaults, secrets_vault.New())
+	env.vaults = append(env.vaults, hashicorp_vault.New())
 
 	// Adding environment functions
-	env.functions = append(env.functions, random.RandomValueFunction{})
+	env.registerFunctions()
 
 	// Reading the values at the beginning
 	if err := env.Sync(); err != nil {
@@ -140,18 +154,47 @@ func (env *Environment) Add(vault, key string, value interface{}) error {
 		return err
 	}
 
+	env.mutex.Lock()
 	if _, ok := env.variables[vault]; !ok {
 		env.variables[vault] = make(map[string]interface{})
 	}
 
 	env.variables[vault][key] = value
+	env.mutex.Unlock()
 
 	notify.Debug("%s.%s: %v", vault, key, fmt.Sprintf("%v", value))
 	return nil
 }
 
+// Set adds a string value to a vault, unlike Add an empty value is kept as it can be a valid
+// result, like the output of a step
+func (env *Environment) Set(vault, key, value string) error {
+	key = strings.ToLower(key)
+
+	if err := guard.EmptyOrNil(vault); err != nil {
+		return err
+	}
+
+	if err := guard.EmptyOrNil(key); err != nil {
+		return err
+	}
+
+	env.mutex.Lock()
+	if _, ok := env.variables[vault]; !ok {
+		env.variables[vault] = make(map[string]interface{})
+	}
+
+	env.variables[vault][key] = value
+	env.mutex.Unlock()
+
+	return nil
+}
+
 func (env *Environment) Remove(vault, key string) error {
 	key = strings.ToLower(key)
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+
 	if err := guard.EmptyOrNil(env.variables[vault]); err != nil {
 		notify.Error(err.Error())
 		return err
@@ -173,9 +216,12 @@ func (env *Environment) Remove(vault, key string) error {
 
 func (env *Environment) Get(vault, key string) interface{} {
 	key = strings.ToLower(key)
+	env.mutex.RLock()
+	defer env.mutex.RUnlock()
+
 	if err := guard.EmptyOrNil(env.variables[vault]); err != nil {
-		notify.Error(err.Error())
-		return err
+		notify.Debug("Vault %s was not found", vault)
+		return nil
 	}
 
 	if _, ok := env.variables[vault]; ok {
@@ -189,6 +235,9 @@ func (env *Environment) Get(vault, key string) interface{} {
 
 func (env *Environment) GetAll(vault string) ([]string, error) {
 	result := make([]string, 0)
+	env.mutex.RLock()
+	defer env.mutex.RUnlock()
+
 	if err := guard.EmptyOrNil(env.variables[vault]); err != nil {
 		notify.Error(err.Error())
 		return result, err
@@ -279,6 +328,15 @@ func (env *Environment) Replace(source string) string {
 	return strings.Join(replacedFragments, "")
 }
 
+// ReplaceText replaces the placeholders of free text like scripts or files, each placeholder is
+// replaced on its own so the text around it, like its whitespace or the }} of go templates, is
+// kept as it is
+func (env *Environment) ReplaceText(source string) string {
+	return placeholderRegex.ReplaceAllStringFunc(source, func(placeholder string) string {
+		return env.Replace(placeholder)
+	})
+}
+
 func (env *Environment) replaceFragments(fragments []string) []string {
 	result := make([]string, len(fragments))
 	for _, fragment := range fragments {
@@ -302,41 +360,46 @@ func (env *Environment) replaceFragments(fragments []string) []string {
 		cleaned := strings.TrimSpace(strings.TrimPrefix(fragment, PREFIX))
 		cleaned = strings.TrimSpace(strings.TrimSuffix(cleaned, SUFFIX))
 
-		parts := strings.Split(cleaned, ".")
-		if len(parts) < 2 {
-			key = cleaned
-		} else {
-			vault = parts[0]
-			key = ""
-			if len(parts) == 2 {
-				key = parts[1]
-			} else {
-				key = strings.Join(parts[1:], ".")
-			}
+		// the functions are split first as their arguments can contain dots
+		functions := make([]string, 0)
+		functionsParts := strings.Split(cleaned, "|")
+		cleaned = strings.TrimSpace(functionsParts[0])
+		if len(functionsParts) > 1 {
+			functions = functionsParts[1:]
 		}
 
-		functions := make([]string, 0)
-		functionsParts := strings.Split(key, "|")
-		if len(functionsParts) > 0 {
-			notify.Debug("found function")
-			key = strings.TrimSpace(functionsParts[0])
-			if len(functionsParts) > 1 {
-				functions = functionsParts[1:]
+		// a placeholder starting with a function, like ${{ uuid }} or ${{ env HOME }}, is replaced
+		// with its result
+		if funcArgs := env.extractFunctionArgs(cleaned); env.isFunction(funcArgs[0]) {
+			value, err := env.execute("", funcArgs...)
+			if err == nil {
+				value, err = env.applyFunctions(value, functions)
+			}
+			if err != nil {
+				notify.Warning("Could not replace %s, %s", fragment, err.Error())
+				result = append(result, fragment)
+				continue
 			}
+			result = append(result, value)
+			continue
 		}
 
-		for _, function := range functions {
-			notify.Debug(function)
+		parts := strings.Split(cleaned, ".")
+		if len(parts) < 2 {
+			key = cleaned
+		} else {
+			vault = parts[0]
+			key = strings.Join(parts[1:], ".")
 		}
 
 		if vault == "" && len(functions) > 0 {
-			for _, function := range functions {
-				funcArgs := env.extractFunctionArgs(function)
-				notify.Debug("Trying to execute with args %s", strings.Join(funcArgs, "."))
-				key = env.execute(fmt.Sprintf("%v", key), funcArgs...)
+			value, err := env.applyFunctions(key, functions)
+			if err != nil {
+				notify.Warning("Could not replace %s, %s", fragment, err.Error())
+				result = append(result, fragment)
+				continue
 			}
-
-			result = append(result, key)
+			result = append(result, value)
 			continue
 		}
 
@@ -351,31 +414,34 @@ func (env *Environment) replaceFragments(fragments []string) []string {
 			if value == nil {
 				notify.Debug("Key %s was not found in vault %s", key, vault)
 
-				for _, function := range functions {
-					funcArgs := env.extractFunctionArgs(function)
-					notify.Debug("Trying to execute with args %s", strings.Join(funcArgs, "."))
-					value = env.execute(fmt.Sprintf("%v", value), funcArgs...)
+				// a missing value is only replaced if it has a default
+				defaultValue, ok, err := env.applyDefault(functions)
+				if err != nil {
+					notify.Warning("Could not replace %s, %s", fragment, err.Error())
+				} else if ok {
+					result = append(result, defaultValue)
+					continue
 				}
 
 				result = append(result, fragment)
 				continue
 			}
 
-			if strings.ContainsAny(fmt.Sprintf("%v", value), PREFIX) && strings.ContainsAny(fmt.Sprintf("%v", value), SUFFIX) {
-				notify.Debug("found nested variable %s", fmt.Sprintf("%v", value))
-				// resetting functions that needs applying as it is a nested value
-				functions = make([]string, 0)
-				value = env.Replace(fmt.Sprintf("%v", value))
+			replaced := fmt.Sprintf("%v", value)
+			if strings.Contains(replaced, PREFIX) && strings.Contains(replaced, SUFFIX) {
+				notify.Debug("found nested variable %s", replaced)
+				replaced = env.Replace(replaced)
 			}
 
-			for _, function := range functions {
-				funcArgs := env.extractFunctionArgs(function)
-				notify.Debug("Trying to execute with args %s", strings.Join(funcArgs, "."))
-				value = env.execute(fmt.Sprintf("%v", value), funcArgs...)
+			replaced, err := env.applyFunctions(replaced, functions)
+			if err != nil {
+				notify.Warning("Could not replace %s, %s", fragment, err.Error())
+				result = append(result, fragment)
+				continue
 			}
 
-			notify.Debug("Key %s was found in vault %s and replaced with %v", key, vault, value)
-			result = append(result, fmt.Sprintf("%v", value))
+			notify.Debug("Key %s was found in vault %s and replaced", key, vault)
+			result = append(result, replaced)
 		}
 	}
 
@@ -420,7 +486,9 @@ func (env *Environment) vaultSync(vaultName string, force bool) error {
 			if err != nil {
 				return err
 			}
+			env.mutex.Lock()
 			env.variables[vaultInterface.Name()] = kv
+			env.mutex.Unlock()
 		} else {
 			notify.Debug("Ignoring the sync of vault %s, not the requested one", vaultInterface.Name())
 		}
@@ -430,17 +498,113 @@ func (env *Environment) vaultSync(vaultName string, force bool) error {
 	return nil
 }
 
+// extractFunctionArgs splits a function call on its spaces, arguments with spaces can be quoted
+// with single or double quotes, ${{ global.name | replace "a b" c }}
 func (env *Environment) extractFunctionArgs(function string) []string {
-	function = strings.TrimSpace(function)
-	return strings.Split(function, " ")
+	args := make([]string, 0)
+	current := strings.Builder{}
+	quote := rune(0)
+	hasArg := false
+
+	for _, char := range strings.TrimSpace(function) {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(char)
+		case char == '"' || char == '\'':
+			quote = char
+			hasArg = true
+		case char == ' ' || char == '\t':
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(char)
+			hasArg = true
+		}
+	}
+
+	if hasArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		args = append(args, "")
+	}
+
+	return args
+}
+
+func (env *Environment) isFunction(name string) bool {
+	_, ok := env.functions[strings.ToLower(name)]
+	return ok
+}
+
+// execute runs the function named in the first argument with the rest of the arguments
+func (env *Environment) execute(value string, args ...string) (string, error) {
+	if len(args) == 0 || args[0] == "" {
+		return value, nil
+	}
+
+	function, ok := env.functions[strings.ToLower(args[0])]
+	if !ok {
+		notify.Warning("Function %s does not exist, ignoring it", args[0])
+		return value, nil
+	}
+
+	notify.Debug("Executing function %s with args %s", args[0], strings.Join(args[1:], " "))
+	return function.New().Exec(value, args[1:]...)
+}
+
+func (env *Environment) applyFunctions(value string, functions []string) (string, error) {
+	for _, function := range functions {
+		args := env.extractFunctionArgs(function)
+		result, err := env.execute(value, args...)
+		if err != nil {
+			return "", fmt.Errorf("function %s failed, %s", args[0], err.Error())
+		}
+		value = result
+	}
+
+	return value, nil
+}
+
+// applyDefault runs the functions of a missing value starting from its first default
+func (env *Environment) applyDefault(functions []string) (string, bool, error) {
+	for i, function := range functions {
+		if strings.EqualFold(env.extractFunctionArgs(function)[0], "default") {
+			value, err := env.applyFunctions("", functions[i:])
+			return value, true, err
+		}
+	}
+
+	return "", false, nil
 }
 
-func (env *Environment) execute(value string, args ...string) string {
-	for _, function := range env.functions {
-		executer := function.New()
-		value = executer.Exec(value, args...)
+func (env *Environment) registerFunctions() {
+	env.functions = make(map[string]env_interfaces.VariableFunction)
+	for _, function := range []env_interfaces.VariableFunction{
+		random.RandomValueFunction{},
+		text.DefaultFunction{},
+		text.UpperFunction{},
+		text.LowerFunction{},
+		text.TrimFunction{},
+		text.ReplaceFunction{},
+		encoding.Base64EncodeFunction{},
+		encoding.Base64DecodeFunction{},
+		encoding.UrlEncodeFunction{},
+		encoding.Sha256Function{},
+		generate.UuidFunction{},
+		generate.NowFunction{},
+		data.JsonFunction{},
+		data.FileFunction{},
+		data.EnvFunction{},
+	} {
+		function = function.New()
+		env.functions[function.Name()] = function
 	}
-	return value
 }
 
 func (env *Environment) addEnvironment(key, value string) error {
@@ -1,7 +1,10 @@
 package environment
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"testing"
 )
 
@@ -175,3 +178,75 @@ func TestEnvironment_Replace(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironment_ReplaceText(t *testing.T) {
+	env := &Environment{variables: map[string]map[string]interface{}{}}
+	env.Add("global", "domain", "locally.internal")
+
+	source := "{{ range .Tenants }}\n  server_name {{ .Name }}.${{ global.domain }};\n{{ end }}\n"
+	want := "{{ range .Tenants }}\n  server_name {{ .Name }}.locally.internal;\n{{ end }}\n"
+	if got := env.ReplaceText(source); got != want {
+		t.Errorf("Environment.ReplaceText() = %q, want %q", got, want)
+	}
+}
+
+func TestEnvironment_ReplaceFunctions(t *testing.T) {
+	env := &Environment{variables: map[string]map[string]interface{}{}}
+	env.registerFunctions()
+	env.Add("global", "name", "  Locally Cli ")
+	env.Add("global", "domain", "locally.internal")
+	env.Add("global", "url", "https://${{ global.domain }}/api")
+	env.Add("global", "encoded", "c2VjcmV0")
+	env.Add("global", "token", `{"access": {"token": "abc"}}`)
+
+	file := filepath.Join(t.TempDir(), "token.txt")
+	if err := os.WriteFile(file, []byte(`{"value": "from file"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOCALLY_TEST_VARIABLE", "from env")
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"upper and trim", "${{ global.name | trim | upper }}", "LOCALLY CLI"},
+		{"lower", "${{ global.domain | lower }}", "locally.internal"},
+		{"replace with quotes", `${{ global.name | trim | replace " " "-" | lower }}`, "locally-cli"},
+		{"trim characters", "${{ global.url | trim /api }}", "https://locally.internal"},
+		{"nested value", "${{ global.url | urlencode }}", "https%3A%2F%2Flocally.internal%2Fapi"},
+		{"base64", "${{ global.encoded | base64decode }}:${{ global.domain | base64encode }}", "secret:bG9jYWxseS5pbnRlcm5hbA=="},
+		{"sha256", "${{ global.domain | sha256 }}", "66fca6e3bfbda3806f9f38811a1fbddefe8ab2fc220c2f08fd437ea4bc5d3e58"},
+		{"json", "${{ global.token | json $.access.token }}", "abc"},
+		{"file", "${{ file " + file + " | json $.value }}", "from file"},
+		{"env", "${{ env LOCALLY_TEST_VARIABLE | upper }}", "FROM ENV"},
+		{"default on a missing value", "${{ global.port | default 8080 }}", "8080"},
+		{"functions after the default", "${{ global.missing | trim | default Some Value | lower }}", "some value"},
+		{"default on an existing value", "${{ global.domain | default other }}", "locally.internal"},
+		{"missing value without default", "${{ global.port | upper }}", "${{ global.port | upper }}"},
+		{"unknown function", "${{ global.domain | unknown }}", "locally.internal"},
+		{"literal", "${{ prefix | upper }}", "PREFIX"},
+		{"text around", "Bearer ${{ global.domain | upper }}!", "Bearer LOCALLY.INTERNAL!"},
+		{"missing json path", "${{ global.token | json $.access.missing }}", "${{ global.token | json $.access.missing }}"},
+		{"missing file", "${{ file " + file + ".missing }}", "${{ file " + file + ".missing }}"},
+		{"missing json path with default", "${{ global.token | json $.missing | default none }}", "${{ global.token | json $.missing | default none }}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := env.Replace(tt.source); got != tt.want {
+				t.Errorf("Environment.Replace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := env.Replace("${{ uuid }}"); !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`).MatchString(got) {
+		t.Errorf("expected a uuid, got %q", got)
+	}
+	if got := env.Replace("${{ now 2006 }}"); !regexp.MustCompile(`^\d{4}$`).MatchString(got) {
+		t.Errorf("expected the current year, got %q", got)
+	}
+	if got := env.Replace("${{ global.domain | random 5 }}"); len(got) != len("locally.internal")+5 {
+		t.Errorf("expected 5 random characters to be appended, got %q", got)
+	}
+}
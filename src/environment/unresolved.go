@@ -0,0 +1,177 @@
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnresolvedReference is a placeholder that was kept in a value because its key was not found
+type UnresolvedReference struct {
+	Placeholder string
+	Source      string
+	Path        string
+}
+
+func (reference UnresolvedReference) String() string {
+	location := reference.Source
+	if location == "" {
+		location = "unknown source"
+	}
+	if reference.Path != "" {
+		location = fmt.Sprintf("%s at %s", location, reference.Path)
+	}
+
+	return fmt.Sprintf("%s in %s", reference.Placeholder, location)
+}
+
+// UnresolvedReferencesError lists all the placeholders that could not be resolved
+type UnresolvedReferencesError struct {
+	References []UnresolvedReference
+}
+
+func (err *UnresolvedReferencesError) Error() string {
+	lines := []string{fmt.Sprintf("found %v unresolved references, add the missing keys or use --no-strict to keep them as they are", len(err.References))}
+	for _, reference := range err.References {
+		lines = append(lines, fmt.Sprintf("  - %s", reference.String()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ReferenceChecker collects the placeholders that cannot be resolved in the values it is given so
+// strict commands can fail once with all of them instead of writing the placeholders to their files
+type ReferenceChecker struct {
+	env        *Environment
+	ignored    []string
+	references []UnresolvedReference
+	found      map[string]bool
+}
+
+// NewReferenceChecker returns a checker that ignores the placeholders that start with any of the
+// given references, like steps or terraform.<stack>, as their values are only known while running
+func (env *Environment) NewReferenceChecker(ignored ...string) *ReferenceChecker {
+	checker := ReferenceChecker{
+		env:        env,
+		ignored:    make([]string, 0),
+		references: make([]UnresolvedReference, 0),
+		found:      make(map[string]bool),
+	}
+	for _, reference := range ignored {
+		checker.ignored = append(checker.ignored, strings.ToLower(reference))
+	}
+
+	return &checker
+}
+
+// Check replaces the placeholders of a value and keeps the ones that were not resolved, strings,
+// maps and slices are checked as they are and any other value is checked by its json fields
+func (checker *ReferenceChecker) Check(source, path string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case string:
+		for _, placeholder := range placeholderRegex.FindAllString(v, -1) {
+			checker.CheckReplaced(source, path, checker.env.Replace(placeholder))
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0)
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			checker.Check(source, joinPath(path, key), v[key])
+		}
+	case map[string]string:
+		keys := make([]string, 0)
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			checker.Check(source, joinPath(path, key), v[key])
+		}
+	case []interface{}:
+		for i, item := range v {
+			checker.Check(source, fmt.Sprintf("%s[%v]", path, i), item)
+		}
+	case []string:
+		for i, item := range v {
+			checker.Check(source, fmt.Sprintf("%s[%v]", path, i), item)
+		}
+	default:
+		content, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		var fields interface{}
+		if err := json.Unmarshal(content, &fields); err != nil {
+			return
+		}
+		// numbers and booleans have no placeholders and would unmarshal to themselves again
+		switch fields.(type) {
+		case string, map[string]interface{}, []interface{}:
+			checker.Check(source, path, fields)
+		}
+	}
+}
+
+// CheckReplaced keeps the placeholders left in a value that was already replaced
+func (checker *ReferenceChecker) CheckReplaced(source, path, value string) {
+	for _, placeholder := range placeholderRegex.FindAllString(value, -1) {
+		if checker.isIgnored(placeholder) {
+			continue
+		}
+
+		reference := UnresolvedReference{
+			Placeholder: placeholder,
+			Source:      source,
+			Path:        path,
+		}
+		if checker.found[reference.String()] {
+			continue
+		}
+
+		checker.found[reference.String()] = true
+		checker.references = append(checker.references, reference)
+	}
+}
+
+func (checker *ReferenceChecker) References() []UnresolvedReference {
+	return checker.references
+}
+
+// Err returns an UnresolvedReferencesError with all the references found or nil if there are none
+func (checker *ReferenceChecker) Err() error {
+	if len(checker.references) == 0 {
+		return nil
+	}
+
+	return &UnresolvedReferencesError{
+		References: checker.references,
+	}
+}
+
+func (checker *ReferenceChecker) isIgnored(placeholder string) bool {
+	reference := strings.TrimSpace(strings.TrimPrefix(placeholder, PREFIX))
+	reference = strings.TrimSpace(strings.TrimSuffix(reference, SUFFIX))
+	reference = strings.ToLower(strings.TrimSpace(strings.Split(reference, "|")[0]))
+
+	for _, ignored := range checker.ignored {
+		if reference == ignored || strings.HasPrefix(reference, ignored+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s", path, key)
+}
@@ -0,0 +1,54 @@
+package environment
+
+import (
+	"testing"
+)
+
+func TestReferenceChecker(t *testing.T) {
+	env := &Environment{variables: map[string]map[string]interface{}{}}
+	env.registerFunctions()
+	env.Add("global", "domain", "locally.internal")
+	env.Add("global", "url", "https://${{ global.subdomain }}.${{ global.domain }}")
+
+	type stack struct {
+		Name      string            `json:"name"`
+		Variables map[string]string `json:"variables"`
+		Ignored   string            `json:"-"`
+	}
+
+	checker := env.NewReferenceChecker("terraform.network")
+	checker.Check("infra.yml", "api", stack{
+		Name: "${{ global.domain }}",
+		Variables: map[string]string{
+			"url":    "${{ global.url }}",
+			"subnet": "${{ terraform.network.subnet_id }}",
+			"vault":  "${{ terraform.vault.id }}",
+		},
+		Ignored: "${{ global.ignored }}",
+	})
+	checker.Check("infra.yml", "api", []string{"${{ global.url }}"})
+	checker.Check("infra.yml", "api", map[string]interface{}{"enabled": true, "port": 8080})
+	checker.CheckReplaced("compose.yml", "web", "image: ${{ global.image | lower }}")
+	checker.CheckReplaced("compose.yml", "web", "host: ${{ global.domain | json $.host }}")
+
+	want := []string{
+		"${{ global.subdomain }} in infra.yml at api.variables.url",
+		"${{ terraform.vault.id }} in infra.yml at api.variables.vault",
+		"${{ global.subdomain }} in infra.yml at api[0]",
+		"${{ global.image | lower }} in compose.yml at web",
+		"${{ global.domain | json $.host }} in compose.yml at web",
+	}
+	references := checker.References()
+	if len(references) != len(want) {
+		t.Fatalf("expected %v references, got %v", len(want), references)
+	}
+	for i, reference := range references {
+		if reference.String() != want[i] {
+			t.Errorf("reference %v = %q, want %q", i, reference.String(), want[i])
+		}
+	}
+
+	if err := env.NewReferenceChecker().Err(); err != nil {
+		t.Errorf("expected no error without references, got %v", err)
+	}
+}
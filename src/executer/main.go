@@ -1,17 +1,24 @@
 package executer
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
+	"time"
 )
 
+const STOP_TIMEOUT = 10 * time.Second
+
 type ExecuteOutput struct {
 	StdOut    string
 	StdErr    string
 	ErrorCode string
+	ExitCode  int
 }
 
 func (exe ExecuteOutput) GetAllOutput() string {
@@ -88,11 +95,30 @@ func ExecuteWithNoOutputContext(ctx context.Context, command string, args ...str
 }
 
 func ExecuteAndWatch(command string, args ...string) (ExecuteOutput, error) {
+	return ExecuteAndWatchContext(context.Background(), "", command, args...)
+}
+
+// ExecuteAndWatchContext runs the command from the given folder without changing the
+// working directory of the current process, when the context is done the command is asked
+// to stop and killed if it did not exit after the STOP_TIMEOUT
+func ExecuteAndWatchContext(ctx context.Context, folder string, command string, args ...string) (ExecuteOutput, error) {
+	return ExecuteAndWatchWithEnvContext(ctx, folder, nil, command, args...)
+}
+
+// ExecuteAndWatchWithEnvContext works like ExecuteAndWatchContext and adds the KEY=value
+// variables to the environment of the command
+func ExecuteAndWatchWithEnvContext(ctx context.Context, folder string, env []string, command string, args ...string) (ExecuteOutput, error) {
 	result := ExecuteOutput{}
-	ctx, cancel := context.WithCancel(context.TODO())
-	defer cancel()
 
 	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = folder
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Cancel = func() error {
+		return interrupt(cmd)
+	}
+	cmd.WaitDelay = STOP_TIMEOUT
 	var stdOut, stdErr, stdIn bytes.Buffer
 
 	cmd.Stdout = io.MultiWriter(os.Stdout, &stdOut)
@@ -105,13 +131,15 @@ func ExecuteAndWatch(command string, args ...string) (ExecuteOutput, error) {
 		return result, err
 	}
 
-	go func() {
-		<-ctx.Done()
-	}()
-
 	if err := cmd.Wait(); err != nil {
 		result.StdErr = stdErr.String()
 		result.StdOut = stdOut.String()
+		if cmd.ProcessState != nil {
+			result.ExitCode = cmd.ProcessState.ExitCode()
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
 		return result, err
 	}
 
@@ -119,3 +147,70 @@ func ExecuteAndWatch(command string, args ...string) (ExecuteOutput, error) {
 	result.StdOut = stdOut.String()
 	return result, nil
 }
+
+// ExecuteAndStreamContext runs the command with the given input and calls onLine for every line
+// the command writes to the standard output, the standard error is shown as it is written, when
+// the context is done the command is stopped like in ExecuteAndWatchContext
+func ExecuteAndStreamContext(ctx context.Context, folder string, input io.Reader, onLine func(line string), command string, args ...string) (ExecuteOutput, error) {
+	result := ExecuteOutput{}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = folder
+	cmd.Cancel = func() error {
+		return interrupt(cmd)
+	}
+	cmd.WaitDelay = STOP_TIMEOUT
+	var stdErr bytes.Buffer
+
+	cmd.Stdin = input
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stdErr)
+	stdOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return result, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		result.StdErr = stdErr.String()
+		return result, err
+	}
+
+	var lines strings.Builder
+	scanner := bufio.NewScanner(stdOut)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines.WriteString(line)
+		lines.WriteString("\n")
+		onLine(line)
+	}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		// the rest of the output is drained so the command does not block writing to it
+		io.Copy(io.Discard, stdOut)
+	}
+
+	err = cmd.Wait()
+	result.StdErr = stdErr.String()
+	result.StdOut = lines.String()
+	if err != nil {
+		if cmd.ProcessState != nil {
+			result.ExitCode = cmd.ProcessState.ExitCode()
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		return result, err
+	}
+
+	return result, scanErr
+}
+
+// interrupt gives the process a chance to clean up, windows does not support sending an
+// interrupt so it gets killed straight away
+func interrupt(cmd *exec.Cmd) error {
+	if runtime.GOOS == "windows" {
+		return cmd.Process.Kill()
+	}
+
+	return cmd.Process.Signal(os.Interrupt)
+}
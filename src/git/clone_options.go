@@ -0,0 +1,34 @@
+package git
+
+import "github.com/cjlapao/locally-cli/context/git_component"
+
+// CloneOptions changes how a repository is cloned and how an existing clone is updated
+type CloneOptions struct {
+	Credentials *git_component.GitCredentials
+	// Clean deletes the destination before cloning, it is ignored when updating
+	Clean bool
+	// Update fetches and fast forwards an existing clone instead of pulling, clones with a ref,
+	// depth or sparse paths are always updated this way
+	Update bool
+	// Ref is the branch, tag or commit to check out, defaults to the default branch
+	Ref string
+	// Depth limits the history cloned to the last commits
+	Depth int
+	// SparsePaths limits the checkout to these folders
+	SparsePaths []string
+	// Submodules initializes and updates the submodules
+	Submodules bool
+}
+
+func (options *CloneOptions) refName() string {
+	if options.Ref == "" {
+		return "the current branch"
+	}
+
+	return options.Ref
+}
+
+// pinned returns true if the clone does not simply follow the default branch
+func (options *CloneOptions) pinned() bool {
+	return options.Ref != "" || options.Depth > 0 || len(options.SparsePaths) > 0
+}
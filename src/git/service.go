@@ -1,11 +1,13 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"io/ioutil"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/cjlapao/common-go/helper"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
 
@@ -53,11 +56,26 @@ func (svc *GitService) Clone(source, destination string, cleanBeforeClone bool)
 }
 
 func (svc *GitService) CloneWithCredentials(source, destination string, gitCredentials *git_component.GitCredentials, cleanBeforeClone bool) error {
+	_, err := svc.CloneWithOptions(source, destination, &CloneOptions{
+		Credentials: gitCredentials,
+		Clean:       cleanBeforeClone,
+	})
+
+	return err
+}
+
+// CloneWithOptions clones the repository to the destination, or gets the latest changes if it was
+// already cloned, and returns the commit checked out
+func (svc *GitService) CloneWithOptions(source, destination string, options *CloneOptions) (string, error) {
 	var publicKey *ssh.PublicKeys
 	config := configuration.Get()
+	if options == nil {
+		options = &CloneOptions{}
+	}
+
 	sourceUrl, err := url.Parse(source)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	sources := helper.JoinPath(config.GetCurrentContext().Configuration.OutputPath, common.SOURCES_PATH)
@@ -70,15 +88,15 @@ func (svc *GitService) CloneWithCredentials(source, destination string, gitCrede
 		}
 	}
 
-	if cleanBeforeClone {
+	if options.Clean && !options.Update {
 		if err := svc.clean(destination); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	source, publicKey, err = InsertCredentials(sourceUrl, gitCredentials)
+	source, publicKey, err = InsertCredentials(sourceUrl, options.Credentials)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	sourceFileCount := 0
@@ -87,55 +105,260 @@ func (svc *GitService) CloneWithCredentials(source, destination string, gitCrede
 		sourceFileCount = len(files)
 		if sourceFileCount == 0 {
 			if err := helper.DeleteFile(destination); err != nil {
-				return err
+				return "", err
 			}
 		}
 	}
 
-	if helper.DirectoryExists(destination) && sourceFileCount > 0 {
-		notify.Info("Destination folder %s already exists, changing to master and getting latest", destination)
-		currentFolder, changeDirErr := os.Getwd()
-		if changeDirErr != nil {
-			return changeDirErr
+	switch {
+	case helper.DirectoryExists(destination) && sourceFileCount > 0 && (options.Update || options.pinned()):
+		// a plain pull fails on the detached head of a tag or commit and ignores the depth and
+		// sparse paths so these clones are always updated
+		notify.Info("Destination folder %s already exists, fetching and fast forwarding %s", destination, options.refName())
+		err = svc.update(destination, options)
+	case helper.DirectoryExists(destination) && sourceFileCount > 0:
+		notify.Info("Destination folder %s already exists, getting the latest changes of the current branch", destination)
+		err = svc.pull(destination)
+	default:
+		notify.Info("Starting to clone %s to %s", sourceUrl, destination)
+		err = svc.clone(source, destination, publicKey, options)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return svc.GetHeadCommit(destination)
+}
+
+// GetHeadCommit returns the sha of the commit checked out in the repository folder
+func (svc *GitService) GetHeadCommit(destination string) (string, error) {
+	repository, err := git.PlainOpen(destination)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+func (svc *GitService) pull(destination string) error {
+	// using -C instead of changing the process folder so concurrent pipeline tasks are not affected
+	runArgs := make([]string, 0)
+	runArgs = append(runArgs, "-C", destination, "pull")
+	if common.IsDebug() {
+		notify.Debug("Run Parameters: %v", fmt.Sprintf("%v", runArgs))
+	}
+
+	output, err := executer.ExecuteWithNoOutput("git", runArgs...)
+	if err != nil {
+		notify.FromError(err, "Something wrong running git pull on %s", destination)
+		if output.GetAllOutput() != "" {
+			notify.Error(output.GetAllOutput())
+		}
+		return err
+	}
+	if common.IsDebug() {
+		notify.Debug("Output: %s", output.GetAllOutput())
+	}
+
+	return nil
+}
+
+// clone clones the ref of the repository without checking it out, so the checkout can be limited
+// to the sparse paths, branches are tried before tags and commits need the full history
+func (svc *GitService) clone(source, destination string, publicKey *ssh.PublicKeys, options *CloneOptions) error {
+	cloneOptions := git.CloneOptions{
+		URL:        source,
+		Progress:   os.Stdout,
+		NoCheckout: true,
+		Depth:      options.Depth,
+	}
+	if publicKey != nil {
+		cloneOptions.Auth = publicKey
+	}
+
+	isCommit := IsCommitSha(options.Ref)
+	if isCommit && options.Depth > 0 {
+		notify.Warning("Ref %s is a commit, cloning the full history of %s", options.Ref, source)
+		cloneOptions.Depth = 0
+	}
+
+	var repository *git.Repository
+	var err error
+	if options.Ref == "" || isCommit {
+		repository, err = git.PlainClone(destination, false, &cloneOptions)
+	} else {
+		for _, referenceName := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(options.Ref), plumbing.NewTagReferenceName(options.Ref)} {
+			cloneOptions.ReferenceName = referenceName
+			cloneOptions.SingleBranch = true
+			repository, err = git.PlainClone(destination, false, &cloneOptions)
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) && !isNoMatchingRef(err) {
+				break
+			}
+
+			notify.Debug("Reference %s was not found in %s", referenceName.String(), source)
+			if cleanErr := svc.clean(destination); cleanErr != nil {
+				return cleanErr
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) || isNoMatchingRef(err) {
+			return fmt.Errorf("ref %s was not found in %s", options.Ref, source)
+		}
+		return err
+	}
+
+	checkoutOptions := git.CheckoutOptions{
+		SparseCheckoutDirectories: options.SparsePaths,
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case isCommit:
+		hash, err := repository.ResolveRevision(plumbing.Revision(options.Ref))
+		if err != nil {
+			return fmt.Errorf("commit %s was not found in %s, %s", options.Ref, source, err.Error())
+		}
+		checkoutOptions.Hash = *hash
+	case head.Name().IsBranch():
+		checkoutOptions.Branch = head.Name()
+	default:
+		checkoutOptions.Hash = head.Hash()
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+
+	if err := worktree.Checkout(&checkoutOptions); err != nil {
+		return err
+	}
+
+	if options.Submodules {
+		submodules, err := worktree.Submodules()
+		if err != nil {
+			return err
+		}
+
+		updateOptions := git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+		}
+		if publicKey != nil {
+			updateOptions.Auth = publicKey
 		}
-		changeDirErr = os.Chdir(destination)
-		if changeDirErr != nil {
-			return changeDirErr
+		if options.Depth > 0 {
+			updateOptions.Depth = 1
 		}
 
-		runArgs := make([]string, 0)
-		runArgs = append(runArgs, "pull")
+		if err := submodules.Update(&updateOptions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// update fetches the ref in an existing checkout and fast forwards it, local changes are kept and
+// the update fails if they conflict or the branch diverged
+func (svc *GitService) update(destination string, options *CloneOptions) error {
+	run := func(args ...string) (string, error) {
+		// using -C instead of changing the process folder so concurrent pipeline tasks are not affected
+		runArgs := append([]string{"-C", destination}, args...)
 		if common.IsDebug() {
 			notify.Debug("Run Parameters: %v", fmt.Sprintf("%v", runArgs))
 		}
 
 		output, err := executer.ExecuteWithNoOutput("git", runArgs...)
+		if err != nil {
+			return "", fmt.Errorf("git %s failed, %s %s", strings.Join(args, " "), err.Error(), strings.TrimSpace(output.GetAllOutput()))
+		}
 
-		changeDirErr = os.Chdir(currentFolder)
-		if changeDirErr != nil {
-			return changeDirErr
+		return strings.TrimSpace(output.StdOut), nil
+	}
+
+	if len(options.SparsePaths) > 0 {
+		if _, err := run(append([]string{"sparse-checkout", "set"}, options.SparsePaths...)...); err != nil {
+			return err
 		}
+	}
 
-		if err != nil {
-			notify.FromError(err, "Something wrong running git pull on master")
-			if output.GetAllOutput() != "" {
-				notify.Error(output.GetAllOutput())
+	fetchArgs := []string{"fetch", "origin"}
+	if options.Depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(options.Depth))
+	}
+
+	switch {
+	case options.Ref == "":
+		if _, err := run(fetchArgs...); err != nil {
+			return err
+		}
+		if _, err := run("merge", "--ff-only", "@{upstream}"); err != nil {
+			return err
+		}
+	case svc.isRemoteBranch(destination, options.Ref):
+		if _, err := run(append(fetchArgs, options.Ref)...); err != nil {
+			return err
+		}
+		if _, err := run("show-ref", "--verify", "--quiet", plumbing.NewBranchReferenceName(options.Ref).String()); err != nil {
+			if _, err := run("checkout", "-b", options.Ref, "FETCH_HEAD"); err != nil {
+				return err
 			}
+		} else if _, err := run("checkout", options.Ref); err != nil {
 			return err
 		}
-		if common.IsDebug() {
-			notify.Debug("Output: %s", output.GetAllOutput())
+		if _, err := run("merge", "--ff-only", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	default:
+		if _, err := run(append(fetchArgs, options.Ref)...); err != nil {
+			return err
+		}
+		if _, err := run("checkout", "--detach", "FETCH_HEAD"); err != nil {
+			return err
 		}
-	} else {
-		notify.Info("Starting to clone %s to %s", sourceUrl, destination)
-		_, err = git.PlainClone(destination, false, &git.CloneOptions{
-			URL:      source,
-			Progress: os.Stdout,
-			Auth:     publicKey,
-		})
 	}
 
-	return err
+	if options.Submodules {
+		if _, err := run("submodule", "update", "--init", "--recursive"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *GitService) isRemoteBranch(destination, ref string) bool {
+	_, err := executer.ExecuteWithNoOutput("git", "-C", destination, "ls-remote", "--exit-code", "--heads", "origin", ref)
+	return err == nil
+}
+
+// IsCommitSha returns true if the ref looks like an abbreviated or full commit sha
+func IsCommitSha(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, char := range ref {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNoMatchingRef(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "couldn't find remote ref")
 }
 
 func getPrivateKey(source string) (*ssh.PublicKeys, error) {
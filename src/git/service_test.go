@@ -0,0 +1,197 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+)
+
+func runGit(t *testing.T, folder string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", folder}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=locally", "GIT_AUTHOR_EMAIL=locally@example.com", "GIT_COMMITTER_NAME=locally", "GIT_COMMITTER_EMAIL=locally@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed, %s %s", strings.Join(args, " "), err.Error(), output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+func commitFile(t *testing.T, folder, name, content string) string {
+	t.Helper()
+	path := filepath.Join(folder, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, folder, "add", "-A")
+	runGit(t, folder, "commit", "-q", "-m", "update "+name)
+
+	return runGit(t, folder, "rev-parse", "HEAD")
+}
+
+func setupRepository(t *testing.T) (string, map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	config := configuration.Get()
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{
+		{Name: "git-test", Configuration: &context_entities.ContextConfiguration{OutputPath: root}},
+	}
+	config.GlobalConfiguration.CurrentContext = "git-test"
+
+	origin := filepath.Join(root, "origin")
+	if err := os.MkdirAll(origin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, origin, "init", "-q", "-b", "main")
+
+	commits := map[string]string{}
+	commits["first"] = commitFile(t, origin, "docs/readme.md", "docs")
+	commits["main"] = commitFile(t, origin, "src/main.go", "package main")
+	runGit(t, origin, "tag", "v1")
+	runGit(t, origin, "checkout", "-q", "-b", "feature")
+	commits["feature"] = commitFile(t, origin, "src/feature.go", "package main")
+	runGit(t, origin, "checkout", "-q", "main")
+
+	return origin, commits
+}
+
+func TestGitService_CloneWithOptions(t *testing.T) {
+	origin, commits := setupRepository(t)
+	svc := Get()
+
+	tests := []struct {
+		name    string
+		options *CloneOptions
+		want    string
+		files   []string
+		missing []string
+	}{
+		{"default branch", &CloneOptions{}, commits["main"], []string{"docs/readme.md", "src/main.go"}, []string{"src/feature.go"}},
+		{"branch", &CloneOptions{Ref: "feature", Depth: 1}, commits["feature"], []string{"src/feature.go"}, nil},
+		{"tag", &CloneOptions{Ref: "v1"}, commits["main"], []string{"src/main.go"}, []string{"src/feature.go"}},
+		{"commit", &CloneOptions{Ref: commits["first"], Depth: 1}, commits["first"], []string{"docs/readme.md"}, []string{"src/main.go"}},
+		{"sparse paths", &CloneOptions{Ref: "feature", SparsePaths: []string{"src"}}, commits["feature"], []string{"src/feature.go"}, []string{"docs/readme.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := filepath.Join(t.TempDir(), "repo")
+			commit, err := svc.CloneWithOptions(origin, destination, tt.options)
+			if err != nil {
+				t.Fatalf("CloneWithOptions() error = %v", err)
+			}
+			if commit != tt.want {
+				t.Errorf("CloneWithOptions() = %s, want %s", commit, tt.want)
+			}
+			for _, file := range tt.files {
+				if _, err := os.Stat(filepath.Join(destination, file)); err != nil {
+					t.Errorf("expected %s to be checked out", file)
+				}
+			}
+			for _, file := range tt.missing {
+				if _, err := os.Stat(filepath.Join(destination, file)); err == nil {
+					t.Errorf("expected %s not to be checked out", file)
+				}
+			}
+		})
+	}
+
+	if _, err := svc.CloneWithOptions(origin, filepath.Join(t.TempDir(), "repo"), &CloneOptions{Ref: "missing"}); err == nil || !strings.Contains(err.Error(), "ref missing was not found") {
+		t.Errorf("expected a missing ref to fail, got %v", err)
+	}
+}
+
+func TestGitService_CloneWithOptions_Update(t *testing.T) {
+	origin, commits := setupRepository(t)
+	svc := Get()
+	destination := filepath.Join(t.TempDir(), "repo")
+
+	options := &CloneOptions{Ref: "feature", Update: true, Clean: true}
+	if _, err := svc.CloneWithOptions(origin, destination, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(destination, "local.txt"), []byte("local change"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, origin, "checkout", "-q", "feature")
+	latest := commitFile(t, origin, "src/latest.go", "package main")
+	if latest == commits["feature"] {
+		t.Fatal("expected a new commit")
+	}
+
+	commit, err := svc.CloneWithOptions(origin, destination, options)
+	if err != nil {
+		t.Fatalf("CloneWithOptions() error = %v", err)
+	}
+	if commit != latest {
+		t.Errorf("expected the checkout to be fast forwarded to %s, got %s", latest, commit)
+	}
+	if _, err := os.Stat(filepath.Join(destination, "local.txt")); err != nil {
+		t.Errorf("expected the local changes to be kept")
+	}
+
+	commit, err = svc.CloneWithOptions(origin, destination, &CloneOptions{Ref: "v1", Update: true})
+	if err != nil {
+		t.Fatalf("CloneWithOptions() error = %v", err)
+	}
+	if commit != commits["main"] {
+		t.Errorf("expected the tag to be checked out, got %s", commit)
+	}
+}
+
+func TestGitService_CloneWithOptions_ExistingRef(t *testing.T) {
+	origin, commits := setupRepository(t)
+	svc := Get()
+
+	for name, ref := range map[string]string{"tag": "v1", "commit": commits["first"]} {
+		t.Run(name, func(t *testing.T) {
+			destination := filepath.Join(t.TempDir(), "repo")
+			options := &CloneOptions{Ref: ref}
+			if _, err := svc.CloneWithOptions(origin, destination, options); err != nil {
+				t.Fatal(err)
+			}
+
+			// the detached head is updated instead of pulled on the next run
+			commit, err := svc.CloneWithOptions(origin, destination, options)
+			if err != nil {
+				t.Fatalf("CloneWithOptions() error = %v", err)
+			}
+			if want := runGit(t, origin, "rev-parse", ref+"^{commit}"); commit != want {
+				t.Errorf("expected %s to stay checked out, got %s", want, commit)
+			}
+		})
+	}
+}
+
+func TestIsCommitSha(t *testing.T) {
+	tests := map[string]bool{
+		"":        false,
+		"main":    false,
+		"v1.0.0":  false,
+		"abc1234": true,
+		"abc123":  false,
+		"deadbeefdeadbeefdeadbeefdeadbeefdeadbeef": true,
+		"DEADBEEF": false,
+	}
+
+	for ref, want := range tests {
+		if got := IsCommitSha(ref); got != want {
+			t.Errorf("IsCommitSha(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
@@ -40,6 +40,7 @@ func ShowHelpForDockerGenerateCommand() {
 	logger.Info("")
 	logger.Info("Options:")
 	logger.Info("  --all          \t\t generates the docker compose override for all services")
+	logger.Info("  --no-strict    \t\t generates the files even if some ${{ }} placeholders cannot be resolved")
 	logger.Info("")
 }
 
@@ -15,6 +15,7 @@ func ShowHelpForInfrastructureCommand() {
 	logger.Info("\t --reconfigure \t reconfigures the backend")
 	logger.Info("\t --clean \t Cleans all of the terraform init configuration")
 	logger.Info("\t --clean-repo \t Deletes the current repository and clones it again")
+	logger.Info("\t --no-strict \t Runs even if some ${{ }} placeholders of the stacks cannot be resolved")
 	logger.Info("")
 	logger.Info("Commands:")
 	logger.Info("  init-backend \t\t Initializes the terraform folder for the stack")
@@ -38,6 +39,7 @@ func ShowHelpForInfrastructureUpCommand() {
 	logger.Info("  --help \t shows command specific help")
 	logger.Info("  --tag=<tag_name>      \t\t select similar stacks based on their tags")
 	logger.Info("  --build-dependencies  \t\t builds the selected stack and all of its dependency chart")
+	logger.Info("  --no-strict           \t\t runs even if some ${{ }} placeholders of the stacks cannot be resolved")
 	logger.Info("")
 }
 
@@ -50,6 +52,7 @@ func ShowHelpForInfrastructureInitCommand() {
 	logger.Info("  --help \t shows command specific help")
 	logger.Info("  --tag=<tag_name>      \t\t select similar stacks based on their tags")
 	logger.Info("  --build-dependencies  \t\t builds the selected stack and all of its dependency chart")
+	logger.Info("  --no-strict           \t\t runs even if some ${{ }} placeholders of the stacks cannot be resolved")
 	logger.Info("  --reconfigure         \t\t restarts the configuration of the stack from fresh")
 	logger.Info("  --clean               \t\t cleans the terraform folders before running the init")
 	logger.Info("")
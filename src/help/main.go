@@ -23,6 +23,7 @@ func ShowHelpForNoCommand() {
 	logger.Info("  hosts           \t\t Controls system host file changes to help generate custom entries")
 	logger.Info("  infrastructure  \t\t Builds the required infrastructure for the services based on the stacks")
 	logger.Info("  pipelines       \t\t Running integrated pipelines for easy manage of services")
+	logger.Info("  secrets         \t\t Keeps the context secrets in an encrypted file")
 	logger.Info("  proxy           \t\t Controls caddy proxy service allowing to generate/update configuration")
 	logger.Info("  nuget           \t\t builds nuget packages and adds them to a local feed")
 	logger.Info("  tools           \t\t Some useful developers tools")
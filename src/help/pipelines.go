@@ -12,6 +12,8 @@ func ShowHelpForPipelinesCommand() {
 	logger.Info("Commands:")
 	logger.Info("  run               \t\t runs the selected pipeline")
 	logger.Info("  validate          \t\t validate the selected pipeline")
+	logger.Info("  resume            \t\t resumes a failed run skipping the tasks that already succeeded")
+	logger.Info("  history           \t\t list the previous runs of the selected pipeline")
 	logger.Info("  list              \t\t list all pipelines")
 }
 
@@ -21,6 +23,11 @@ func ShowHelpForPipelineRunCommand() {
 	logger.Info("Runs the selected pipeline")
 	logger.Info("")
 	logger.Info("Options:")
+	logger.Info("  --max-parallel=<number> \t\t maximum number of tasks running at the same time, defaults to 1")
+	logger.Info("  --dry-run               \t\t validates the pipeline and shows what each task would do without running it")
+	logger.Info("  --no-strict             \t\t runs the pipeline even if some ${{ }} placeholders cannot be resolved")
+	logger.Info("  --param <name>=<value>  \t\t value of a pipeline parameter, can be used more than once")
+	logger.Info("  --report <format>=<path>\t\t writes a junit or json report of the tasks, can be used more than once")
 	logger.Info("")
 }
 
@@ -30,6 +37,27 @@ func ShowHelpForPipelineValidateCommand() {
 	logger.Info("Validates the selected pipeline")
 	logger.Info("")
 	logger.Info("Options:")
+	logger.Info("  --report <format>=<path>\t\t writes a junit or json report of the tasks, can be used more than once")
+	logger.Info("")
+}
+
+func ShowHelpForPipelineResumeCommand() {
+	logger.Info("Usage: locally pipeline resume [RUN_ID]")
+	logger.Info("")
+	logger.Info("Resumes a previous run of a pipeline, tasks that succeeded in that run are not executed again")
+	logger.Info("")
+	logger.Info("Options:")
+	logger.Info("  --param <name>=<value>  \t\t value of a pipeline parameter, defaults to the parameters of the resumed run")
+	logger.Info("  --max-parallel=<number> \t\t maximum number of tasks running at the same time, defaults to 1")
+	logger.Info("  --no-strict             \t\t runs the pipeline even if some ${{ }} placeholders cannot be resolved")
+	logger.Info("  --report <format>=<path>\t\t writes a junit or json report of the tasks, can be used more than once")
+	logger.Info("")
+}
+
+func ShowHelpForPipelineHistoryCommand() {
+	logger.Info("Usage: locally pipeline history [PIPELINE]")
+	logger.Info("")
+	logger.Info("Lists the previous runs of the selected pipeline")
 	logger.Info("")
 }
 
@@ -0,0 +1,60 @@
+package help
+
+func ShowHelpForSecretsCommand() {
+	logger.Info("Usage: locally secrets command")
+	logger.Info("")
+	logger.Info("locally secrets")
+	logger.Info("")
+	logger.Info("Keeps the secrets of the current context in an encrypted file, they can be used as ${{ secrets.name }}")
+	logger.Info("")
+	logger.Info("Options:")
+	logger.Info("\t --help \t shows command specific help")
+	logger.Info("")
+	logger.Info("Commands:")
+	logger.Info("  set         \t\t sets the value of a secret")
+	logger.Info("  get         \t\t prints the value of a secret")
+	logger.Info("  list        \t\t lists the names of the secrets")
+	logger.Info("  rm          \t\t removes a secret")
+	logger.Info("  rotate      \t\t encrypts the secrets with a new key")
+	logger.Info("")
+	logger.Info("Environment Variables:")
+	logger.Info("  LOCALLY_SECRETS_PASSPHRASE     \t\t passphrase used to encrypt the secrets, without it a key file is used")
+	logger.Info("  LOCALLY_SECRETS_NEW_PASSPHRASE \t\t passphrase used by rotate to encrypt the secrets again")
+	logger.Info("")
+}
+
+func ShowHelpForSecretsSetCommand() {
+	logger.Info("Usage: locally secrets set [NAME] [VALUE]")
+	logger.Info("")
+	logger.Info("Sets the value of a secret, when the value is empty or - it is read from the input")
+	logger.Info("")
+}
+
+func ShowHelpForSecretsGetCommand() {
+	logger.Info("Usage: locally secrets get [NAME]")
+	logger.Info("")
+	logger.Info("Prints the value of a secret")
+	logger.Info("")
+}
+
+func ShowHelpForSecretsListCommand() {
+	logger.Info("Usage: locally secrets list")
+	logger.Info("")
+	logger.Info("Lists the names of the secrets, the values are never printed")
+	logger.Info("")
+}
+
+func ShowHelpForSecretsRemoveCommand() {
+	logger.Info("Usage: locally secrets rm [NAME]")
+	logger.Info("")
+	logger.Info("Removes a secret")
+	logger.Info("")
+}
+
+func ShowHelpForSecretsRotateCommand() {
+	logger.Info("Usage: locally secrets rotate")
+	logger.Info("")
+	logger.Info("Encrypts the secrets with the passphrase in LOCALLY_SECRETS_NEW_PASSPHRASE or, when it is")
+	logger.Info("not set, with a new random key that replaces the key file")
+	logger.Info("")
+}
@@ -18,6 +18,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 	notify := notifications.Get()
 
 	terraformSvc.CheckForTerraform(false)
+	strict := !helper.GetFlagSwitch("no-strict", false)
 
 	if subCommand == "" && helper.GetFlagSwitch("help", false) {
 		help.ShowHelpForInfrastructureCommand()
@@ -48,6 +49,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -77,6 +79,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -106,6 +109,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -134,6 +138,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -158,6 +163,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: false,
+				Strict:            strict,
 			}
 		}
 
@@ -178,6 +184,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: false,
+				Strict:            strict,
 				StdOutput:         true,
 			}
 		}
@@ -208,6 +215,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -237,6 +245,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -266,11 +275,20 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			}
 		}
 
+		// Checking all the stacks at once so a missing value does not stop the up half way
+		if strict {
+			if err := terraformSvc.CheckReferences(stacks); err != nil {
+				notify.Error("There are unresolved references in the infrastructure stacks, %s", err.Error())
+				return
+			}
+		}
+
 		// We need to init and validate all dependent stacks before we commit to plan and apply
 		for _, dependentStack := range stacks {
 			dependencyOptions := &TerraformServiceOptions{
 				Name:              dependentStack.Name,
 				BuildDependencies: false,
+				Strict:            strict,
 			}
 			terraformSvc.InitiateStack(dependencyOptions)
 			if !notify.HasErrors() {
@@ -285,6 +303,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 				dependencyOptions := &TerraformServiceOptions{
 					Name:              dependentStack.Name,
 					BuildDependencies: false,
+					Strict:            strict,
 				}
 				notify.Reset()
 				terraformSvc.PlanStack(dependencyOptions)
@@ -323,6 +342,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -352,6 +372,7 @@ func Operations(subCommand, stack string, options *TerraformServiceOptions) {
 			options = &TerraformServiceOptions{
 				Name:              stack,
 				BuildDependencies: buildDependencies,
+				Strict:            strict,
 			}
 		}
 
@@ -44,6 +44,7 @@ type TerraformServiceOptions struct {
 	RootFolder        string
 	StackPath         string
 	StdOutput         bool
+	Strict            bool
 }
 
 func New() *TerraformService {
@@ -347,6 +348,13 @@ func (svc *TerraformService) InitiateStack(options *TerraformServiceOptions) {
 		notify.Warning("No infrastructure stacks found")
 	}
 
+	if options.Strict {
+		if err := svc.checkReferences(stacks, false); err != nil {
+			notify.Error("There are unresolved references in the infrastructure stacks, %s", err.Error())
+			return
+		}
+	}
+
 	for _, stack := range stacks {
 		stackPath, pathError := svc.getPath(stack, options)
 		if pathError != nil {
@@ -459,6 +467,13 @@ func (svc *TerraformService) PlanStack(options *TerraformServiceOptions) *PlanCh
 		}
 	}
 
+	if options.Strict {
+		if err := svc.CheckReferences(stacks); err != nil {
+			notify.Error("There are unresolved references in the infrastructure stacks, %s", err.Error())
+			return nil
+		}
+	}
+
 	for _, stack := range stacks {
 		stackPath, pathError := svc.getPath(stack, options)
 		if pathError != nil {
@@ -567,6 +582,13 @@ func (svc *TerraformService) ApplyStack(options *TerraformServiceOptions) {
 		}
 	}
 
+	if options.Strict {
+		if err := svc.CheckReferences(stacks); err != nil {
+			notify.Error("There are unresolved references in the infrastructure stacks, %s", err.Error())
+			return
+		}
+	}
+
 	for _, stack := range stacks {
 		stackPath, pathError := svc.getPath(stack, options)
 		if pathError != nil {
@@ -714,6 +736,13 @@ func (svc *TerraformService) DestroyStack(options *TerraformServiceOptions) {
 		}
 	}
 
+	if options.Strict {
+		if err := svc.CheckReferences(stacks); err != nil {
+			notify.Error("There are unresolved references in the infrastructure stacks, %s", err.Error())
+			return
+		}
+	}
+
 	dependency_tree.ReverseDependency(stacks)
 	stackNames := ""
 
@@ -976,6 +1005,33 @@ func (svc *TerraformService) GraphStack(options *TerraformServiceOptions) {
 	}
 }
 
+// CheckReferences returns all the placeholders of the stacks that cannot be resolved, the outputs
+// of the stacks in the list are ignored as they are only known once the stack is applied
+func (svc *TerraformService) CheckReferences(stacks []*infrastructure_component.InfrastructureStack) error {
+	return svc.checkReferences(stacks, true)
+}
+
+// checkReferences checks the locations and backend of the stacks and, when requested, their
+// variables as the init does not need them
+func (svc *TerraformService) checkReferences(stacks []*infrastructure_component.InfrastructureStack, variables bool) error {
+	ignored := make([]string, 0)
+	for _, stack := range stacks {
+		ignored = append(ignored, fmt.Sprintf("terraform.%s", common.EncodeName(stack.Name)))
+	}
+
+	checker := environment.Get().NewReferenceChecker(ignored...)
+	for _, stack := range stacks {
+		checker.Check(stack.Source, fmt.Sprintf("%s.location", stack.Name), stack.Location)
+		checker.Check(stack.Source, fmt.Sprintf("%s.repository", stack.Name), stack.Repository)
+		checker.Check(stack.Source, fmt.Sprintf("%s.backend", stack.Name), stack.Backend)
+		if variables {
+			checker.Check(stack.Source, fmt.Sprintf("%s.variables", stack.Name), stack.Variables)
+		}
+	}
+
+	return checker.Err()
+}
+
 func (svc *TerraformService) CanClone(stack *infrastructure_component.InfrastructureStack) bool {
 	if stack == nil {
 		notify.Debug("Stack is nil, ignoring")
@@ -0,0 +1,83 @@
+package lanes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+)
+
+const (
+	ErrorCancelled = "499"
+)
+
+// time a worker has to return after its context was cancelled before we stop waiting for it
+const workerStopTimeout = 15 * time.Second
+
+// parseTimeout accepts a duration like 30s, 5m or 1h30m, a plain number is read as seconds
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := common.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout == 0 && strings.TrimSpace(value) != "" {
+		return 0, fmt.Errorf("timeout %s needs to be greater than 0", value)
+	}
+
+	return timeout, nil
+}
+
+// withTimeout derives a context from the parent that is cancelled after the timeout, without
+// a timeout the context is only cancelled with its parent
+func withTimeout(parent context.Context, value string) (context.Context, context.CancelFunc, error) {
+	timeout, err := parseTimeout(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(parent)
+		return ctx, cancel, nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return ctx, cancel, nil
+}
+
+// stoppedError explains why something stopped once its context is done
+func stoppedError(ctx context.Context, kind, name string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s %s timed out", kind, name)
+	}
+
+	return fmt.Errorf("%s %s was cancelled", kind, name)
+}
+
+// runWorker runs the task in the worker and stops waiting for it if the worker does not
+// return in time after the context was cancelled
+func runWorker(ctx context.Context, worker interfaces.PipelineWorker, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	done := make(chan entities.PipelineWorkerResult, 1)
+	go func() {
+		done <- worker.Run(ctx, task)
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+	}
+
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(workerStopTimeout):
+		notify.Warning("[%s] task %s did not stop %s after being cancelled, no longer waiting for it", worker.Name(), task.Name, workerStopTimeout.String())
+		return entities.NewPipelineWorkerResultFromError(ErrorCancelled, ctx.Err())
+	}
+}
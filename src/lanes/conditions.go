@@ -0,0 +1,44 @@
+package lanes
+
+import (
+	"fmt"
+
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/expressions"
+)
+
+// shouldRun evaluates the if condition of a pipeline, job or step, without a condition it only
+// runs while nothing failed, the same happens for conditions that do not check the status
+func shouldRun(condition string, status *runStatus) (bool, error) {
+	if condition == "" {
+		return !status.failed(), nil
+	}
+
+	if !expressions.UsesStatus(condition) && status.failed() {
+		return false, nil
+	}
+
+	return expressions.Evaluate(condition, &expressions.Context{
+		Failed:  status.failed(),
+		Resolve: resolveReference,
+	})
+}
+
+func resolveReference(reference string) (interface{}, bool) {
+	env := environment.Get()
+	placeholder := fmt.Sprintf("%s %s %s", environment.PREFIX, reference, environment.SUFFIX)
+	value := env.Replace(placeholder)
+	if value == placeholder {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func validateCondition(condition string) error {
+	if condition == "" {
+		return nil
+	}
+
+	return expressions.Validate(condition)
+}
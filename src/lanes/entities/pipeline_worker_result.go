@@ -5,11 +5,22 @@ import "fmt"
 type PipelineWorkerResult struct {
 	State      PipelineWorkerResultState
 	Output     string
+	Outputs    map[string]string
 	ErrorCode  string
 	StatusCode string
+	ExitCode   string
 	Error      error
 }
 
+// AddOutput publishes a named value that later steps can use as ${{ steps.<step>.outputs.<name> }}
+func (a *PipelineWorkerResult) AddOutput(name, value string) {
+	if a.Outputs == nil {
+		a.Outputs = make(map[string]string)
+	}
+
+	a.Outputs[name] = value
+}
+
 func (a PipelineWorkerResult) String() string {
 	switch a.State {
 	case StateValid:
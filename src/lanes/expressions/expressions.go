@@ -0,0 +1,287 @@
+package expressions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Context is what an expression is evaluated against, Failed is the status used by the
+// success() and failure() functions and Resolve looks up references like global.some_key
+type Context struct {
+	Failed  bool
+	Resolve func(reference string) (interface{}, bool)
+}
+
+type function struct {
+	arguments int
+	exec      func(ctx *Context, args []interface{}) interface{}
+}
+
+var functions = map[string]function{
+	"success": {
+		arguments: 0,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return !ctx.Failed
+		},
+	},
+	"failure": {
+		arguments: 0,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return ctx.Failed
+		},
+	},
+	"always": {
+		arguments: 0,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return true
+		},
+	},
+	"contains": {
+		arguments: 2,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return strings.Contains(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))
+		},
+	},
+	"startswith": {
+		arguments: 2,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return strings.HasPrefix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))
+		},
+	},
+	"endswith": {
+		arguments: 2,
+		exec: func(ctx *Context, args []interface{}) interface{} {
+			return strings.HasSuffix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))
+		},
+	},
+}
+
+// Evaluate parses and evaluates the expression returning its truthiness
+func Evaluate(expression string, ctx *Context) (bool, error) {
+	root, err := parse(expression)
+	if err != nil {
+		return false, err
+	}
+
+	if ctx == nil {
+		ctx = &Context{}
+	}
+
+	value, err := root.eval(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return isTruthy(value), nil
+}
+
+// Validate checks if the expression can be parsed without evaluating it
+func Validate(expression string) error {
+	_, err := parse(expression)
+	return err
+}
+
+// UsesStatus returns true if the expression calls success(), failure() or always(), expressions
+// without any of them are only evaluated while nothing failed
+func UsesStatus(expression string) bool {
+	root, err := parse(expression)
+	if err != nil {
+		return false
+	}
+
+	return usesStatus(root)
+}
+
+func usesStatus(current node) bool {
+	switch n := current.(type) {
+	case callNode:
+		if n.name == "success" || n.name == "failure" || n.name == "always" {
+			return true
+		}
+		for _, argument := range n.arguments {
+			if usesStatus(argument) {
+				return true
+			}
+		}
+	case unaryNode:
+		return usesStatus(n.operand)
+	case binaryNode:
+		return usesStatus(n.left) || usesStatus(n.right)
+	}
+
+	return false
+}
+
+type node interface {
+	eval(ctx *Context) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(ctx *Context) (interface{}, error) {
+	return n.value, nil
+}
+
+type referenceNode struct {
+	name string
+}
+
+func (n referenceNode) eval(ctx *Context) (interface{}, error) {
+	if ctx.Resolve == nil {
+		return nil, nil
+	}
+
+	value, ok := ctx.Resolve(n.name)
+	if !ok {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+type callNode struct {
+	name      string
+	arguments []node
+}
+
+func (n callNode) eval(ctx *Context) (interface{}, error) {
+	args := make([]interface{}, 0)
+	for _, argument := range n.arguments {
+		value, err := argument.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	return functions[n.name].exec(ctx, args), nil
+}
+
+type unaryNode struct {
+	operand node
+}
+
+func (n unaryNode) eval(ctx *Context) (interface{}, error) {
+	value, err := n.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return !isTruthy(value), nil
+}
+
+type binaryNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n binaryNode) eval(ctx *Context) (interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.operator {
+	case "&&":
+		if !isTruthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	case "||":
+		if isTruthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	}
+
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return compare(n.operator, left, right), nil
+}
+
+func compare(operator string, left, right interface{}) bool {
+	leftNumber, leftErr := strconv.ParseFloat(toString(left), 64)
+	rightNumber, rightErr := strconv.ParseFloat(toString(right), 64)
+	numeric := leftErr == nil && rightErr == nil
+
+	switch operator {
+	case "==":
+		if numeric {
+			return leftNumber == rightNumber
+		}
+		return strings.EqualFold(toString(left), toString(right))
+	case "!=":
+		if numeric {
+			return leftNumber != rightNumber
+		}
+		return !strings.EqualFold(toString(left), toString(right))
+	case "<":
+		if numeric {
+			return leftNumber < rightNumber
+		}
+		return toString(left) < toString(right)
+	case "<=":
+		if numeric {
+			return leftNumber <= rightNumber
+		}
+		return toString(left) <= toString(right)
+	case ">":
+		if numeric {
+			return leftNumber > rightNumber
+		}
+		return toString(left) > toString(right)
+	case ">=":
+		if numeric {
+			return leftNumber >= rightNumber
+		}
+		return toString(left) >= toString(right)
+	}
+
+	return false
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		trimmed := strings.TrimSpace(v)
+		return trimmed != "" && !strings.EqualFold(trimmed, "false") && trimmed != "0"
+	default:
+		return true
+	}
+}
@@ -0,0 +1,73 @@
+package expressions
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	values := map[string]interface{}{
+		"global.environment":          "dev",
+		"global.replicas":             "3",
+		"steps.apply.status":          "success",
+		"steps.get-token.outputs.url": "https://example.com/api",
+	}
+	resolve := func(reference string) (interface{}, bool) {
+		value, ok := values[reference]
+		return value, ok
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		failed     bool
+		want       bool
+		wantErr    bool
+	}{
+		{"success without failures", "success()", false, true, false},
+		{"success with failures", "success()", true, false, false},
+		{"failure with failures", "failure()", true, true, false},
+		{"always", "always()", true, true, false},
+		{"string equality is case insensitive", "global.environment == 'DEV'", false, true, false},
+		{"reference syntax", "${{ global.environment }} != 'prod'", false, true, false},
+		{"numeric comparison", "global.replicas >= 2", false, true, false},
+		{"missing reference is empty", "global.missing == ''", false, true, false},
+		{"and or precedence", "false && true || true", false, true, false},
+		{"negation with parenthesis", "!(steps.apply.status == 'success')", false, false, false},
+		{"contains", "contains(steps.get-token.outputs.url, 'example')", false, true, false},
+		{"startsWith", "startsWith(steps.get-token.outputs.url, 'https')", false, true, false},
+		{"unknown function", "unknown()", false, false, true},
+		{"wrong number of arguments", "contains('a')", false, false, true},
+		{"unterminated string", "'abc", false, false, true},
+		{"empty expression", "", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.expression, &Context{Failed: tt.failed, Resolve: resolve})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsesStatus(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"global.environment == 'dev'", false},
+		{"always()", true},
+		{"failure() && global.environment == 'dev'", true},
+		{"'failure()' == global.environment", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if got := UsesStatus(tt.expression); got != tt.want {
+				t.Errorf("UsesStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
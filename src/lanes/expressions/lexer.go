@@ -0,0 +1,103 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenString
+	tokenNumber
+	tokenIdentifier
+	tokenReference
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenType
+	value string
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+func tokenize(expression string) ([]token, error) {
+	result := make([]token, 0)
+	runes := []rune(expression)
+	pos := 0
+
+	for pos < len(runes) {
+		current := runes[pos]
+		switch {
+		case unicode.IsSpace(current):
+			pos += 1
+		case current == '(':
+			result = append(result, token{kind: tokenLeftParen, value: "("})
+			pos += 1
+		case current == ')':
+			result = append(result, token{kind: tokenRightParen, value: ")"})
+			pos += 1
+		case current == ',':
+			result = append(result, token{kind: tokenComma, value: ","})
+			pos += 1
+		case current == '\'' || current == '"':
+			end := pos + 1
+			for end < len(runes) && runes[end] != current {
+				end += 1
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at position %d", pos)
+			}
+			result = append(result, token{kind: tokenString, value: string(runes[pos+1 : end])})
+			pos = end + 1
+		case strings.HasPrefix(string(runes[pos:]), "${{"):
+			end := strings.Index(string(runes[pos:]), "}}")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated reference starting at position %d", pos)
+			}
+			inner := string(runes[pos : pos+end+2])
+			result = append(result, token{kind: tokenReference, value: inner})
+			pos += len([]rune(inner))
+		case unicode.IsDigit(current) || (current == '-' && pos+1 < len(runes) && unicode.IsDigit(runes[pos+1])):
+			end := pos + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end += 1
+			}
+			result = append(result, token{kind: tokenNumber, value: string(runes[pos:end])})
+			pos = end
+		case isIdentifierRune(current):
+			end := pos + 1
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end += 1
+			}
+			result = append(result, token{kind: tokenIdentifier, value: string(runes[pos:end])})
+			pos = end
+		default:
+			found := false
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[pos:]), operator) {
+					result = append(result, token{kind: tokenOperator, value: operator})
+					pos += len(operator)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %s at position %d", string(current), pos)
+			}
+		}
+	}
+
+	result = append(result, token{kind: tokenEOF})
+	return result, nil
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
@@ -0,0 +1,213 @@
+package expressions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(expression string) (node, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("expression cannot be empty")
+	}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{
+		tokens: tokens,
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.current().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s in expression %s", p.current().value, expression)
+	}
+
+	return root, nil
+}
+
+func (p *parser) current() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	current := p.tokens[p.pos]
+	if current.kind != tokenEOF {
+		p.pos += 1
+	}
+	return current
+}
+
+func (p *parser) isOperator(values ...string) bool {
+	current := p.current()
+	if current.kind != tokenOperator {
+		return false
+	}
+
+	for _, value := range values {
+		if current.value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isOperator("==", "!=", "<", "<=", ">", ">=") {
+		operator := p.next().value
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{operator: operator, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	current := p.next()
+	switch current.kind {
+	case tokenLeftParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRightParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	case tokenString:
+		return literalNode{value: current.value}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", current.value)
+		}
+		return literalNode{value: value}, nil
+	case tokenReference:
+		name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(current.value, "${{"), "}}"))
+		return referenceNode{name: name}, nil
+	case tokenIdentifier:
+		switch strings.ToLower(current.value) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+
+		if p.current().kind == tokenLeftParen {
+			return p.parseCall(current.value)
+		}
+
+		return referenceNode{name: current.value}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %s", current.value)
+	}
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	name = strings.ToLower(name)
+	definition, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+
+	// consuming the opening parenthesis
+	p.next()
+	arguments := make([]node, 0)
+	if p.current().kind != tokenRightParen {
+		for {
+			argument, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+
+			if p.current().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.next().kind != tokenRightParen {
+		return nil, fmt.Errorf("missing closing parenthesis for function %s", name)
+	}
+
+	if len(arguments) != definition.arguments {
+		return nil, fmt.Errorf("function %s expects %d argument(s) but got %d", name, definition.arguments, len(arguments))
+	}
+
+	return callNode{name: name, arguments: arguments}, nil
+}
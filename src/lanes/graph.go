@@ -0,0 +1,186 @@
+package lanes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graphItem is anything in a pipeline that can declare dependencies by name, like jobs
+// and steps
+type graphItem interface {
+	GetName() string
+	GetDependencies() []string
+}
+
+type executionNode[T graphItem] struct {
+	index      int
+	item       T
+	dependsOn  []int
+	requiredBy []int
+}
+
+// executionGraph is the DAG built from the dependsOn of a list of jobs or steps, the nodes
+// keep their original file order so it is used as the tie breaker when scheduling
+type executionGraph[T graphItem] struct {
+	nodes []*executionNode[T]
+}
+
+type executionNodeResult struct {
+	index int
+	err   error
+}
+
+func buildExecutionGraph[T graphItem](items []T) (*executionGraph[T], error) {
+	graph := executionGraph[T]{
+		nodes: make([]*executionNode[T], 0),
+	}
+
+	names := make(map[string]int)
+	for idx, item := range items {
+		name := strings.ToLower(item.GetName())
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("found more than one item with the name %s", item.GetName())
+		}
+
+		names[name] = idx
+		graph.nodes = append(graph.nodes, &executionNode[T]{
+			index:      idx,
+			item:       item,
+			dependsOn:  make([]int, 0),
+			requiredBy: make([]int, 0),
+		})
+	}
+
+	for _, node := range graph.nodes {
+		for _, dependency := range node.item.GetDependencies() {
+			dependencyIndex, ok := names[strings.ToLower(dependency)]
+			if !ok {
+				return nil, fmt.Errorf("dependency on %s of %s was not found", dependency, node.item.GetName())
+			}
+			if dependencyIndex == node.index {
+				return nil, fmt.Errorf("%s cannot depend on itself", node.item.GetName())
+			}
+
+			node.dependsOn = append(node.dependsOn, dependencyIndex)
+			graph.nodes[dependencyIndex].requiredBy = append(graph.nodes[dependencyIndex].requiredBy, node.index)
+		}
+	}
+
+	if _, err := graph.order(); err != nil {
+		return nil, err
+	}
+
+	return &graph, nil
+}
+
+// Order returns the items in the order they would be executed if there was no parallelism
+func (graph *executionGraph[T]) Order() []T {
+	result := make([]T, 0)
+	order, _ := graph.order()
+	for _, idx := range order {
+		result = append(result, graph.nodes[idx].item)
+	}
+
+	return result
+}
+
+func (graph *executionGraph[T]) order() ([]int, error) {
+	result := make([]int, 0)
+	pending := make([]int, len(graph.nodes))
+	ready := make([]int, 0)
+	for _, node := range graph.nodes {
+		pending[node.index] = len(node.dependsOn)
+		if pending[node.index] == 0 {
+			ready = append(ready, node.index)
+		}
+	}
+
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		result = append(result, current)
+		for _, next := range graph.nodes[current].requiredBy {
+			pending[next] -= 1
+			if pending[next] == 0 {
+				ready = insertSorted(ready, next)
+			}
+		}
+	}
+
+	if len(result) != len(graph.nodes) {
+		cycle := make([]string, 0)
+		for _, node := range graph.nodes {
+			if pending[node.index] > 0 {
+				cycle = append(cycle, node.item.GetName())
+			}
+		}
+		return result, fmt.Errorf("found a circular dependency between %s", strings.Join(cycle, ", "))
+	}
+
+	return result, nil
+}
+
+// Run executes every node once all of its dependencies finished successfully, running at most
+// maxParallel nodes at the same time. Once a node fails no other node will be started and the
+// first error is returned after the running ones finish
+func (graph *executionGraph[T]) Run(maxParallel int, run func(item T) error) error {
+	if maxParallel <= 0 {
+		maxParallel = 1
+	}
+
+	pending := make([]int, len(graph.nodes))
+	ready := make([]int, 0)
+	for _, node := range graph.nodes {
+		pending[node.index] = len(node.dependsOn)
+		if pending[node.index] == 0 {
+			ready = append(ready, node.index)
+		}
+	}
+
+	done := make(chan executionNodeResult)
+	running := 0
+	var firstErr error
+
+	for {
+		for firstErr == nil && running < maxParallel && len(ready) > 0 {
+			current := ready[0]
+			ready = ready[1:]
+			running += 1
+			go func(node *executionNode[T]) {
+				done <- executionNodeResult{
+					index: node.index,
+					err:   run(node.item),
+				}
+			}(graph.nodes[current])
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-done
+		running -= 1
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			continue
+		}
+
+		for _, next := range graph.nodes[result.index].requiredBy {
+			pending[next] -= 1
+			if pending[next] == 0 {
+				ready = insertSorted(ready, next)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func insertSorted(values []int, value int) []int {
+	values = append(values, value)
+	sort.Ints(values)
+	return values
+}
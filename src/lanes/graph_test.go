@@ -0,0 +1,154 @@
+package lanes
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testGraphItem struct {
+	name      string
+	dependsOn []string
+}
+
+func (item testGraphItem) GetName() string {
+	return item.name
+}
+
+func (item testGraphItem) GetDependencies() []string {
+	return item.dependsOn
+}
+
+func TestExecutionGraph_Order(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []testGraphItem
+		want    []string
+		wantErr bool
+	}{
+		{
+			"no dependencies keeps file order",
+			[]testGraphItem{{name: "a"}, {name: "b"}, {name: "c"}},
+			[]string{"a", "b", "c"},
+			false,
+		},
+		{
+			"dependency declared later moves forward",
+			[]testGraphItem{{name: "a", dependsOn: []string{"c"}}, {name: "b"}, {name: "c"}},
+			[]string{"b", "c", "a"},
+			false,
+		},
+		{
+			"dependencies are case insensitive",
+			[]testGraphItem{{name: "build", dependsOn: []string{"Clone"}}, {name: "clone"}},
+			[]string{"clone", "build"},
+			false,
+		},
+		{
+			"missing dependency",
+			[]testGraphItem{{name: "a", dependsOn: []string{"x"}}},
+			nil,
+			true,
+		},
+		{
+			"circular dependency",
+			[]testGraphItem{{name: "a", dependsOn: []string{"b"}}, {name: "b", dependsOn: []string{"a"}}},
+			nil,
+			true,
+		},
+		{
+			"duplicated names",
+			[]testGraphItem{{name: "a"}, {name: "A"}},
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph, err := buildExecutionGraph(tt.items)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildExecutionGraph() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, 0)
+			for _, item := range graph.Order() {
+				got = append(got, item.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("executionGraph.Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionGraph_Run(t *testing.T) {
+	items := []testGraphItem{
+		{name: "a"},
+		{name: "b"},
+		{name: "c", dependsOn: []string{"a", "b"}},
+	}
+	graph, err := buildExecutionGraph(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mutex sync.Mutex
+	running := 0
+	maxRunning := 0
+	finished := make([]string, 0)
+	err = graph.Run(2, func(item testGraphItem) error {
+		mutex.Lock()
+		running += 1
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mutex.Lock()
+		running -= 1
+		finished = append(finished, item.name)
+		mutex.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxRunning != 2 {
+		t.Errorf("expected a and b to run in parallel, max running was %v", maxRunning)
+	}
+	if finished[len(finished)-1] != "c" {
+		t.Errorf("expected c to run last, got %v", finished)
+	}
+}
+
+func TestExecutionGraph_RunStopsOnError(t *testing.T) {
+	items := []testGraphItem{
+		{name: "a"},
+		{name: "b", dependsOn: []string{"a"}},
+	}
+	graph, err := buildExecutionGraph(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executed := make([]string, 0)
+	err = graph.Run(1, func(item testGraphItem) error {
+		executed = append(executed, item.name)
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !reflect.DeepEqual(executed, []string{"a"}) {
+		t.Errorf("expected only a to be executed, got %v", executed)
+	}
+}
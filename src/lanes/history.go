@@ -0,0 +1,296 @@
+package lanes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+
+	"github.com/cjlapao/common-go/helper"
+	"github.com/google/uuid"
+)
+
+const (
+	PIPELINE_RUNS_FOLDER = "runs"
+)
+
+const (
+	RunStateRunning   = "running"
+	RunStateSucceeded = "succeeded"
+	RunStateFailed    = "failed"
+)
+
+// PipelineRunRecord is what is persisted for every pipeline run, it allows to list the history
+// of a pipeline and to resume a failed run skipping the tasks that already succeeded
+type PipelineRunRecord struct {
+	ID          string                `json:"id"`
+	Pipeline    string                `json:"pipeline"`
+	ResumedFrom string                `json:"resumedFrom,omitempty"`
+	Parameters  map[string]string     `json:"parameters,omitempty"`
+	State       string                `json:"state"`
+	Error       string                `json:"error,omitempty"`
+	StartedAt   time.Time             `json:"startedAt"`
+	FinishedAt  time.Time             `json:"finishedAt,omitempty"`
+	Tasks       []*PipelineTaskRecord `json:"tasks"`
+	mutex       sync.Mutex
+}
+
+type PipelineTaskRecord struct {
+	Job        string            `json:"job"`
+	Step       string            `json:"step"`
+	State      string            `json:"state"`
+	Resumed    bool              `json:"resumed,omitempty"`
+	ErrorCode  string            `json:"errorCode,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	Output     string            `json:"output,omitempty"`
+	Outputs    map[string]string `json:"outputs,omitempty"`
+}
+
+func (task *PipelineTaskRecord) Duration() time.Duration {
+	return task.FinishedAt.Sub(task.StartedAt)
+}
+
+func newPipelineRunRecord(pipeline string) *PipelineRunRecord {
+	id := fmt.Sprintf("%s-%s", time.Now().Format("20060102150405"), strings.Split(uuid.NewString(), "-")[0])
+	record := PipelineRunRecord{
+		ID:        id,
+		Pipeline:  pipeline,
+		State:     RunStateRunning,
+		StartedAt: time.Now(),
+		Tasks:     make([]*PipelineTaskRecord, 0),
+	}
+
+	return &record
+}
+
+func (record *PipelineRunRecord) Duration() time.Duration {
+	if record.FinishedAt.IsZero() {
+		return 0
+	}
+
+	return record.FinishedAt.Sub(record.StartedAt)
+}
+
+// GetTask returns the last record of a task in the run
+func (record *PipelineRunRecord) GetTask(job, step string) *PipelineTaskRecord {
+	record.mutex.Lock()
+	defer record.mutex.Unlock()
+
+	var result *PipelineTaskRecord
+	for _, task := range record.Tasks {
+		if strings.EqualFold(task.Job, job) && strings.EqualFold(task.Step, step) {
+			result = task
+		}
+	}
+
+	return result
+}
+
+// FailedTask returns the first task that errored in the run
+func (record *PipelineRunRecord) FailedTask() *PipelineTaskRecord {
+	record.mutex.Lock()
+	defer record.mutex.Unlock()
+
+	for _, task := range record.Tasks {
+		if task.State == entities.StateErrored.String() {
+			return task
+		}
+	}
+
+	return nil
+}
+
+func (record *PipelineRunRecord) addTask(task *PipelineTaskRecord) {
+	record.mutex.Lock()
+	record.Tasks = append(record.Tasks, task)
+	record.mutex.Unlock()
+
+	if err := record.save(); err != nil {
+		notify.Warning("There was an error saving the run %s, %s", record.ID, err.Error())
+	}
+}
+
+func (record *PipelineRunRecord) finish(err error) {
+	record.mutex.Lock()
+	record.FinishedAt = time.Now()
+	if err != nil {
+		record.State = RunStateFailed
+		record.Error = err.Error()
+	} else {
+		record.State = RunStateSucceeded
+	}
+	record.mutex.Unlock()
+
+	if err := record.save(); err != nil {
+		notify.Warning("There was an error saving the run %s, %s", record.ID, err.Error())
+	}
+}
+
+func (record *PipelineRunRecord) save() error {
+	return record.saveTo(getRunsFolder())
+}
+
+// persistedRunRecord is the run as it is written to disk, the errors and outputs of the tasks
+// are masked, the files are only readable by the user as the named outputs that do not look like
+// secrets are kept as they are for resume
+type persistedRunRecord struct {
+	*PipelineRunRecord
+	Error string                `json:"error,omitempty"`
+	Tasks []*PipelineTaskRecord `json:"tasks"`
+}
+
+func (record *PipelineRunRecord) saveTo(root string) error {
+	record.mutex.Lock()
+	defer record.mutex.Unlock()
+
+	folder := helper.JoinPath(root, common.EncodeName(record.Pipeline))
+	if err := os.MkdirAll(folder, 0o700); err != nil {
+		return err
+	}
+	// folders created by older versions are readable by everyone
+	for _, path := range []string{root, folder} {
+		if err := os.Chmod(path, 0o700); err != nil {
+			return err
+		}
+	}
+
+	env := environment.Get()
+	persisted := persistedRunRecord{
+		PipelineRunRecord: record,
+		Error:             env.Mask(record.Error),
+		Tasks:             make([]*PipelineTaskRecord, 0),
+	}
+	for _, task := range record.Tasks {
+		masked := *task
+		masked.Output = env.Mask(task.Output)
+		masked.Error = env.Mask(task.Error)
+		masked.Outputs = maskOutputs(env, task.Outputs)
+		persisted.Tasks = append(persisted.Tasks, &masked)
+	}
+
+	content, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := helper.JoinPath(folder, fmt.Sprintf("%s.json", record.ID))
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0o600)
+}
+
+// maskOutputs masks the named outputs that hold a known secret or have a name that looks like a
+// secret, like token or password
+func maskOutputs(env *environment.Environment, outputs map[string]string) map[string]string {
+	if outputs == nil {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for key, value := range outputs {
+		if value != "" && environment.IsSecretKey(key) {
+			result[key] = environment.MASK
+			continue
+		}
+		result[key] = env.Mask(value)
+	}
+
+	return result
+}
+
+// isMasked checks if the output of a task was masked when it was saved
+func (task *PipelineTaskRecord) isMasked() bool {
+	if strings.Contains(task.Output, environment.MASK) {
+		return true
+	}
+	for _, value := range task.Outputs {
+		if strings.Contains(value, environment.MASK) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getRunsFolder() string {
+	config := configuration.Get()
+	return helper.JoinPath(config.GetCurrentContext().Configuration.OutputPath, common.PIPELINES_PATH, PIPELINE_RUNS_FOLDER)
+}
+
+func readPipelineRunRecord(path string) (*PipelineRunRecord, error) {
+	content, err := helper.ReadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var record PipelineRunRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// GetRun loads a persisted run by its id
+func (automation *PipelineService) GetRun(id string) (*PipelineRunRecord, error) {
+	return findPipelineRunRecord(getRunsFolder(), id)
+}
+
+// GetHistory returns the persisted runs of a pipeline, the most recent first
+func (automation *PipelineService) GetHistory(pipeline string) ([]*PipelineRunRecord, error) {
+	return readPipelineHistory(getRunsFolder(), pipeline)
+}
+
+func findPipelineRunRecord(root, id string) (*PipelineRunRecord, error) {
+	matches, err := filepath.Glob(helper.JoinPath(root, "*", fmt.Sprintf("%s.json", id)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("run %s was not found", id)
+	}
+
+	return readPipelineRunRecord(matches[0])
+}
+
+func readPipelineHistory(root, pipeline string) ([]*PipelineRunRecord, error) {
+	result := make([]*PipelineRunRecord, 0)
+	folder := helper.JoinPath(root, common.EncodeName(pipeline))
+	if !helper.DirectoryExists(folder) {
+		return result, nil
+	}
+
+	matches, err := filepath.Glob(helper.JoinPath(folder, "*.json"))
+	if err != nil {
+		return result, err
+	}
+
+	for _, match := range matches {
+		record, err := readPipelineRunRecord(match)
+		if err != nil {
+			notify.Warning("Could not read the run %s, %s", match, err.Error())
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package lanes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestPipelineRunRecord_Save(t *testing.T) {
+	environment.Get().Add("secrets", "history-token", "s3cr3t-history-token")
+
+	root := filepath.Join(t.TempDir(), "runs")
+	record := newPipelineRunRecord("Seed Database")
+	record.Tasks = append(record.Tasks,
+		&PipelineTaskRecord{Job: "auth", Step: "login", State: entities.StateExecuted.String(), Output: `{"token":"s3cr3t-history-token"}`, Outputs: map[string]string{"token": "plain-looking-token", "header": "Bearer s3cr3t-history-token", "tenant": "acme"}},
+		&PipelineTaskRecord{Job: "database", Step: "migrate", State: entities.StateErrored.String(), Error: "login failed with Password=hunter22"},
+	)
+	record.State = RunStateFailed
+	record.Error = "step failed with s3cr3t-history-token"
+
+	if err := record.saveTo(root); err != nil {
+		t.Fatal(err)
+	}
+
+	folder := filepath.Join(root, "seed_database")
+	path := filepath.Join(folder, record.ID+".json")
+	for file, want := range map[string]os.FileMode{root: 0o700, folder: 0o700, path: 0o600} {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("%s has permissions %v, want %v", file, info.Mode().Perm(), want)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cr3t-history-token", "hunter22", "plain-looking-token"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("secret %s was saved without a mask, %s", secret, content)
+		}
+	}
+
+	saved, err := findPipelineRunRecord(root, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"token": environment.MASK, "header": "Bearer " + environment.MASK, "tenant": "acme"}
+	if !reflect.DeepEqual(saved.Tasks[0].Outputs, want) {
+		t.Errorf("unexpected saved outputs %v, want %v", saved.Tasks[0].Outputs, want)
+	}
+	if record.Tasks[0].Outputs["token"] != "plain-looking-token" {
+		t.Errorf("the outputs in memory should not be changed, got %v", record.Tasks[0].Outputs)
+	}
+	if saved.Tasks[0].Output != `{"token":"`+environment.MASK+`"}` || saved.Error != "step failed with "+environment.MASK {
+		t.Errorf("unexpected masked values %q %q", saved.Tasks[0].Output, saved.Error)
+	}
+	if record.Tasks[0].Output != `{"token":"s3cr3t-history-token"}` {
+		t.Errorf("the record in memory should not be changed, got %q", record.Tasks[0].Output)
+	}
+
+	if _, err := findPipelineRunRecord(root, "missing"); err == nil {
+		t.Errorf("expected an error for a missing run")
+	}
+}
+
+func TestReadPipelineHistory(t *testing.T) {
+	root := t.TempDir()
+	started := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for id, offset := range map[string]time.Duration{"first": 0, "second": time.Minute, "third": 2 * time.Minute} {
+		record := &PipelineRunRecord{ID: id, Pipeline: "seed", State: RunStateSucceeded, StartedAt: started.Add(offset), Tasks: []*PipelineTaskRecord{}}
+		if err := record.saveTo(root); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := readPipelineHistory(root, "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 0)
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+	if strings.Join(ids, ",") != "third,second,first" {
+		t.Errorf("expected the most recent runs first, got %v", ids)
+	}
+
+	if runs, err := readPipelineHistory(root, "unknown"); err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs for an unknown pipeline, got %v %v", runs, err)
+	}
+}
+
+func TestPipelineRun_SucceededBefore(t *testing.T) {
+	previous := newPipelineRunRecord("seed")
+	previous.Tasks = []*PipelineTaskRecord{
+		{Job: "database", Step: "clone", State: entities.StateErrored.String()},
+		{Job: "database", Step: "clone", State: entities.StateExecuted.String(), Outputs: map[string]string{"commit": "abc"}},
+		{Job: "database", Step: "migrate", State: entities.StateErrored.String(), Error: "failed"},
+		{Job: "database", Step: "login", State: entities.StateExecuted.String(), Outputs: map[string]string{"token": environment.MASK}},
+	}
+	previous.State = RunStateFailed
+
+	pipeline := &pipeline_component.Pipeline{Name: "seed"}
+	job := &pipeline_component.PipelineJob{Name: "database"}
+	run := newPipelineRun(pipeline, &PipelineRunOptions{MaxParallel: 1}, previous)
+	if run.record.ResumedFrom != previous.ID {
+		t.Errorf("the run should record the resumed run, got %q", run.record.ResumedFrom)
+	}
+
+	if task := run.succeededBefore(job, &pipeline_component.PipelineTask{Name: "clone"}); task == nil || task.Outputs["commit"] != "abc" {
+		t.Errorf("expected the last clone record to be reused, got %v", task)
+	}
+	if task := run.succeededBefore(job, &pipeline_component.PipelineTask{Name: "migrate"}); task != nil {
+		t.Errorf("failed tasks should run again, got %v", task)
+	}
+	if task := run.succeededBefore(job, &pipeline_component.PipelineTask{Name: "seed"}); task != nil {
+		t.Errorf("tasks that did not run should run, got %v", task)
+	}
+	if task := run.succeededBefore(job, &pipeline_component.PipelineTask{Name: "login"}); task != nil {
+		t.Errorf("tasks with masked outputs should run again, got %v", task)
+	}
+	if failed := previous.FailedTask(); failed == nil || failed.Step != "clone" {
+		t.Errorf("expected the first failed task, got %v", failed)
+	}
+
+	run.cleanup = true
+	if task := run.succeededBefore(job, &pipeline_component.PipelineTask{Name: "clone"}); task != nil {
+		t.Errorf("cleanup jobs should always run again, got %v", task)
+	}
+}
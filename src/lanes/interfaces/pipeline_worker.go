@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 )
@@ -8,6 +9,12 @@ import (
 type PipelineWorker interface {
 	Name() string
 	New() PipelineWorker
-	Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult
+	Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult
 	Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult
 }
+
+// PipelineWorkerPlanner is implemented by workers that can describe what they would do with a
+// task without doing it, the description is returned in the Output of a valid result
+type PipelineWorkerPlanner interface {
+	Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult
+}
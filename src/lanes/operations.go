@@ -1,11 +1,16 @@
 package lanes
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/configuration"
 	"github.com/cjlapao/locally-cli/help"
+	"github.com/cjlapao/locally-cli/icons"
 
 	"github.com/cjlapao/common-go/helper"
 )
@@ -27,8 +32,33 @@ func Operations(subCommand string) {
 			help.ShowHelpForPipelineRunCommand()
 			os.Exit(0)
 		}
-		if err := pipelinesService.Validate(pipeline); err == nil {
-			if err := pipelinesService.Run(pipeline); err != nil {
+		maxParallel, err := strconv.Atoi(helper.GetFlagValue("max-parallel", "1"))
+		if err != nil || maxParallel < 1 {
+			notify.Error("Invalid value for --max-parallel, it needs to be a number greater than 0")
+			return
+		}
+
+		parameters, err := getParameterFlags()
+		if err != nil {
+			notify.FromError(err, "Invalid --param value")
+			return
+		}
+		reports, err := getReportFlags()
+		if err != nil {
+			notify.FromError(err, "Invalid --report value")
+			return
+		}
+
+		options := &PipelineRunOptions{
+			MaxParallel: maxParallel,
+			DryRun:      helper.GetFlagSwitch("dry-run", false),
+			Strict:      !helper.GetFlagSwitch("no-strict", false),
+			Parameters:  parameters,
+		}
+
+		defer writeReports(pipelinesService, reports)
+		if err := pipelinesService.Validate(pipeline, options); err == nil {
+			if err := pipelinesService.Run(pipeline, options); err != nil {
 				notify.Error("There was an error executing the requested pipeline %s", pipeline)
 			}
 		} else {
@@ -39,9 +69,82 @@ func Operations(subCommand string) {
 			help.ShowHelpForPipelineValidateCommand()
 			os.Exit(0)
 		}
-		if err := pipelinesService.Validate(pipeline); err != nil {
+		reports, err := getReportFlags()
+		if err != nil {
+			notify.FromError(err, "Invalid --report value")
+			return
+		}
+
+		defer writeReports(pipelinesService, reports)
+		if err := pipelinesService.Validate(pipeline, nil); err != nil {
 			notify.Error("There was an error validating the requested pipeline %s", pipeline)
 		}
+	case "resume":
+		if helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForPipelineResumeCommand()
+			os.Exit(0)
+		}
+		runId := pipeline
+		if runId == "" {
+			notify.Error("Missing the id of the run to resume")
+			return
+		}
+		maxParallel, err := strconv.Atoi(helper.GetFlagValue("max-parallel", "1"))
+		if err != nil || maxParallel < 1 {
+			notify.Error("Invalid value for --max-parallel, it needs to be a number greater than 0")
+			return
+		}
+
+		record, err := pipelinesService.GetRun(runId)
+		if err != nil {
+			notify.FromError(err, "There was an error loading the run %s", runId)
+			return
+		}
+
+		parameters, err := getParameterFlags()
+		if err != nil {
+			notify.FromError(err, "Invalid --param value")
+			return
+		}
+
+		reports, err := getReportFlags()
+		if err != nil {
+			notify.FromError(err, "Invalid --report value")
+			return
+		}
+
+		options := &PipelineRunOptions{
+			MaxParallel: maxParallel,
+			ResumeFrom:  record.ID,
+			Strict:      !helper.GetFlagSwitch("no-strict", false),
+			Parameters:  parameters,
+		}
+
+		defer writeReports(pipelinesService, reports)
+		if err := pipelinesService.Validate(record.Pipeline, options); err == nil {
+			if err := pipelinesService.Run(record.Pipeline, options); err != nil {
+				notify.Error("There was an error resuming the run %s of pipeline %s", runId, record.Pipeline)
+			}
+		} else {
+			notify.Error("There was an error validating the requested pipeline %s", record.Pipeline)
+		}
+	case "history":
+		if helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForPipelineHistoryCommand()
+			os.Exit(0)
+		}
+		if pipeline == "" {
+			notify.Error("Missing the name of the pipeline")
+			return
+		}
+
+		runs, err := pipelinesService.GetHistory(pipeline)
+		if err != nil {
+			notify.FromError(err, "There was an error loading the history of pipeline %s", pipeline)
+			return
+		}
+
+		listHistory(pipeline, runs)
 	case "list":
 		if helper.GetFlagSwitch("help", false) {
 			help.ShowHelpForPipelineListCommand()
@@ -56,3 +159,103 @@ func Operations(subCommand string) {
 	}
 
 }
+
+func listHistory(pipeline string, runs []*PipelineRunRecord) {
+	notify.InfoWithIcon(icons.IconClipboard, "Listing the runs of pipeline %s", pipeline)
+	if len(runs) == 0 {
+		notify.InfoIndentIcon(icons.IconBlackSquare, "No runs found", "  ")
+		return
+	}
+
+	for _, run := range runs {
+		icon := icons.IconHourGlass
+		switch run.State {
+		case RunStateSucceeded:
+			icon = icons.IconCheckMark
+		case RunStateFailed:
+			icon = icons.IconCrossMark
+		}
+
+		line := fmt.Sprintf("%s %s started at %s", run.ID, run.State, run.StartedAt.Format(time.RFC3339))
+		if duration := run.Duration(); duration > 0 {
+			line = fmt.Sprintf("%s took %s", line, duration.Round(time.Millisecond).String())
+		}
+		if run.ResumedFrom != "" {
+			line = fmt.Sprintf("%s, resumed from %s", line, run.ResumedFrom)
+		}
+		notify.InfoIndentIcon(icon, "%s", "  ", line)
+
+		if failed := run.FailedTask(); failed != nil {
+			notify.InfoIndentIcon(icons.IconRightArrow, "failed at %s.%s, %s", "    ", failed.Job, failed.Step, failed.Error)
+		}
+	}
+}
+
+// getParameterFlags reads all the --param name=value flags, they can also be written as
+// --param=name=value
+func getParameterFlags() (map[string]string, error) {
+	result := make(map[string]string)
+	values, err := getFlagValues("param")
+	if err != nil {
+		return result, err
+	}
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return result, fmt.Errorf("invalid parameter %s, it needs to be name=value", value)
+		}
+		result[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	return result, nil
+}
+
+// getReportFlags reads all the --report format=path flags
+func getReportFlags() ([]*PipelineReportTarget, error) {
+	result := make([]*PipelineReportTarget, 0)
+	values, err := getFlagValues("report")
+	if err != nil {
+		return result, err
+	}
+
+	for _, value := range values {
+		target, err := ParseReportTarget(value)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, target)
+	}
+
+	return result, nil
+}
+
+// getFlagValues returns all the values of a flag that can be given more than once, either as
+// --name value or --name=value
+func getFlagValues(name string) ([]string, error) {
+	result := make([]string, 0)
+	flag := fmt.Sprintf("--%s", name)
+	args := os.Args
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == flag:
+			if i+1 >= len(args) {
+				return result, fmt.Errorf("missing value for %s", flag)
+			}
+			i += 1
+			result = append(result, args[i])
+		case strings.HasPrefix(args[i], flag+"="):
+			result = append(result, strings.TrimPrefix(args[i], flag+"="))
+		}
+	}
+
+	return result, nil
+}
+
+// writeReports writes the requested reports, this is done even when the pipeline failed as
+// that is when they are needed the most
+func writeReports(service *PipelineService, targets []*PipelineReportTarget) {
+	if err := service.WriteReports(targets); err != nil {
+		notify.FromError(err, "There was an error writing the pipeline reports")
+	}
+}
@@ -0,0 +1,172 @@
+package lanes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/vaults/pipeline_vault"
+)
+
+const (
+	stepStatusSuccess = "success"
+	stepStatusFailure = "failure"
+	stepStatusSkipped = "skipped"
+)
+
+const (
+	pipelineStatusSuccess   = "success"
+	pipelineStatusFailure   = "failure"
+	pipelineStatusCancelled = "cancelled"
+)
+
+const (
+	outputJsonPrefix  = "json:"
+	outputRegexPrefix = "regex:"
+	outputLinePrefix  = "line:"
+	outputRaw         = "output"
+)
+
+// resetOutputs registers the steps and pipeline vaults in the environment and clears any values
+// left behind by a previous pipeline
+func resetOutputs() error {
+	env := environment.Get()
+	for _, vault := range []*pipeline_vault.PipelineVault{pipeline_vault.Get(), pipeline_vault.GetStatus()} {
+		vault.Clear()
+		if err := env.Register(vault); err != nil {
+			return err
+		}
+
+		if err := env.RefreshVault(vault.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishPipelineStatus sets the outcome of the main jobs of a pipeline so the onFailure and
+// finally jobs can use ${{ pipeline.status }}, ${{ pipeline.failed_job }},
+// ${{ pipeline.failed_step }} and ${{ pipeline.error }}
+func publishPipelineStatus(ctx context.Context, status *runStatus) error {
+	env := environment.Get()
+	vault := pipeline_vault.GetStatus()
+	job, step, err := status.failure()
+
+	state := pipelineStatusSuccess
+	message := ""
+	if err != nil {
+		state = pipelineStatusFailure
+		message = err.Error()
+	}
+	if ctx.Err() != nil {
+		state = pipelineStatusCancelled
+	}
+
+	vault.Clear()
+	vault.Set("status", state)
+	vault.Set("failed_job", job)
+	vault.Set("failed_step", step)
+	vault.Set("error", message)
+
+	return env.RefreshVault(vault.Name())
+}
+
+// publishOutputs adds the raw output, the outputs set by the worker and the outputs declared
+// in the task to the steps vault
+func publishOutputs(task *pipeline_component.PipelineTask, result entities.PipelineWorkerResult) (map[string]string, error) {
+	outputs := make(map[string]string)
+	for key, value := range result.Outputs {
+		outputs[key] = value
+	}
+
+	for key, expression := range task.Outputs {
+		value, err := extractOutput(result, expression)
+		if err != nil {
+			return outputs, fmt.Errorf("could not extract output %s from task %s, %s", key, task.Name, err.Error())
+		}
+		outputs[key] = value
+	}
+
+	restoreOutputs(task, result.Output, outputs)
+	return outputs, nil
+}
+
+// restoreOutputs adds already extracted outputs to the steps vault, this is also used when
+// resuming a run so the tasks that are not executed again still provide their outputs
+func restoreOutputs(task *pipeline_component.PipelineTask, output string, outputs map[string]string) {
+	setOutput(fmt.Sprintf("%s.output", task.Name), strings.TrimSpace(output))
+	for key, value := range outputs {
+		setOutput(fmt.Sprintf("%s.outputs.%s", task.Name, key), value)
+	}
+}
+
+// publishStatus sets the ${{ steps.<step>.status }} of a step
+func publishStatus(task *pipeline_component.PipelineTask, status string) {
+	setOutput(fmt.Sprintf("%s.status", task.Name), status)
+}
+
+// setOutput adds a value to the steps vault, empty values are published as well so the steps
+// using them do not keep the placeholder
+func setOutput(key, value string) {
+	vault := pipeline_vault.Get()
+	vault.Set(key, value)
+	if err := environment.Get().Set(vault.Name(), key, value); err != nil {
+		notify.Warning("Could not publish the output %s, %s", key, err.Error())
+	}
+}
+
+// extractOutput resolves an output expression against the result of a task, the expression
+// can be one of:
+//   - output: the whole output of the task
+//   - json:<path>: the value of a json path in the output
+//   - regex:<expression>: the first group, or the whole match, of a regular expression
+//   - line:<index>: a line of the output, negative indexes count from the end
+//   - <name>: an output published by the worker
+func extractOutput(result entities.PipelineWorkerResult, expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+	switch {
+	case expression == outputRaw:
+		return strings.TrimSpace(result.Output), nil
+	case strings.HasPrefix(expression, outputJsonPrefix):
+		return common.GetJsonPathString(result.Output, strings.TrimPrefix(expression, outputJsonPrefix))
+	case strings.HasPrefix(expression, outputRegexPrefix):
+		re, err := regexp.Compile(strings.TrimPrefix(expression, outputRegexPrefix))
+		if err != nil {
+			return "", err
+		}
+		match := re.FindStringSubmatch(result.Output)
+		if match == nil {
+			return "", fmt.Errorf("expression %s did not match the output", expression)
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	case strings.HasPrefix(expression, outputLinePrefix):
+		index, err := strconv.Atoi(strings.TrimPrefix(expression, outputLinePrefix))
+		if err != nil {
+			return "", fmt.Errorf("invalid line index in %s", expression)
+		}
+		lines := strings.Split(strings.TrimRight(strings.ReplaceAll(result.Output, "\r\n", "\n"), "\n"), "\n")
+		if index < 0 {
+			index = len(lines) + index
+		}
+		if index < 0 || index >= len(lines) {
+			return "", fmt.Errorf("line %s is out of range, output has %s lines", strings.TrimPrefix(expression, outputLinePrefix), strconv.Itoa(len(lines)))
+		}
+		return strings.TrimSpace(lines[index]), nil
+	default:
+		value, ok := result.Outputs[expression]
+		if !ok {
+			return "", fmt.Errorf("worker did not publish an output named %s", expression)
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,90 @@
+package lanes
+
+import (
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestExtractOutput(t *testing.T) {
+	result := entities.PipelineWorkerResult{
+		Output:  "{\"access_token\":\"abc\",\"items\":[{\"id\":7}]}\nversion 1.2.3\ndone\n",
+		Outputs: map[string]string{"statusCode": "200", "empty": ""},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{"raw output", "output", "{\"access_token\":\"abc\",\"items\":[{\"id\":7}]}\nversion 1.2.3\ndone", false},
+		{"worker output", "statusCode", "200", false},
+		{"empty worker output", "empty", "", false},
+		{"missing worker output", "tenantId", "", true},
+		{"regex group", `regex:version (\d+\.\d+\.\d+)`, "1.2.3", false},
+		{"regex match", `regex:\d+\.\d+`, "1.2", false},
+		{"regex without match", `regex:build (\d+)`, "", true},
+		{"invalid regex", "regex:(", "", true},
+		{"first line", "line:1", "version 1.2.3", false},
+		{"last line", "line:-1", "done", false},
+		{"line out of range", "line:5", "", true},
+		{"invalid line", "line:last", "", true},
+		{"json path on a text output", "json:$.access_token", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractOutput(result, tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("extractOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	json := entities.PipelineWorkerResult{Output: `{"access_token":"abc","items":[{"id":7}],"empty":""}`}
+	for expression, want := range map[string]string{"json:$.access_token": "abc", "json:$.items[0].id": "7", "json:$.empty": ""} {
+		if got, err := extractOutput(json, expression); err != nil || got != want {
+			t.Errorf("extractOutput(%s) = %q, %v, want %q", expression, got, err, want)
+		}
+	}
+	if _, err := extractOutput(json, "json:$.refresh_token"); err == nil {
+		t.Errorf("expected an error for a missing json path")
+	}
+}
+
+func TestPublishOutputs(t *testing.T) {
+	if err := resetOutputs(); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &pipeline_component.PipelineTask{
+		Name:    "login",
+		Outputs: map[string]string{"token": "json:$.token", "refresh": "json:$.refresh"},
+	}
+	result := entities.PipelineWorkerResult{Output: `{"token":"abc","refresh":""}`, Outputs: map[string]string{"statusCode": "200"}}
+
+	outputs, err := publishOutputs(task, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 3 || outputs["token"] != "abc" || outputs["statusCode"] != "200" {
+		t.Errorf("unexpected outputs %v", outputs)
+	}
+
+	env := environment.Get()
+	got := env.Replace("${{ steps.login.outputs.token }}:${{ steps.login.outputs.refresh }}:${{ steps.login.outputs.statusCode }}")
+	if got != "abc::200" {
+		t.Errorf("outputs were not published, got %q", got)
+	}
+
+	task.Outputs = map[string]string{"missing": "json:$.missing"}
+	if _, err := publishOutputs(task, result); err == nil {
+		t.Errorf("expected an error for an output that cannot be extracted")
+	}
+}
@@ -0,0 +1,153 @@
+package lanes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/vaults/pipeline_vault"
+)
+
+var parameterReferenceRegex = regexp.MustCompile(`\$\{\{\s*parameters\.([A-Za-z0-9_\-]+)\s*\}\}`)
+
+// validateParameterDefinitions checks the parameters declared by a pipeline or a template
+func validateParameterDefinitions(definitions []*pipeline_component.PipelineParameter) error {
+	names := make(map[string]bool)
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return fmt.Errorf("parameters need a name")
+		}
+		name := strings.ToLower(definition.Name)
+		if names[name] {
+			return fmt.Errorf("parameter %s is declared more than once", definition.Name)
+		}
+		names[name] = true
+
+		switch definition.GetType() {
+		case pipeline_component.ParameterTypeString, pipeline_component.ParameterTypeBool, pipeline_component.ParameterTypeInt:
+		case pipeline_component.ParameterTypeChoice:
+			if len(definition.Values) == 0 {
+				return fmt.Errorf("choice parameter %s needs a list of values", definition.Name)
+			}
+		default:
+			return fmt.Errorf("parameter %s has an invalid type %s, it needs to be one of string, bool, int or choice", definition.Name, definition.Type)
+		}
+
+		if !definition.IsRequired() {
+			if _, err := parseParameterValue(definition, fmt.Sprintf("%v", definition.Default)); err != nil {
+				return fmt.Errorf("invalid default value, %s", err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveParameters checks the given values against the parameter definitions and fills in the
+// defaults, missing required parameters are only an error when required is true
+func resolveParameters(definitions []*pipeline_component.PipelineParameter, values map[string]string, required bool) (map[string]string, error) {
+	result := make(map[string]string)
+	if err := validateParameterDefinitions(definitions); err != nil {
+		return result, err
+	}
+
+	known := make(map[string]bool)
+	for _, definition := range definitions {
+		known[strings.ToLower(definition.Name)] = true
+	}
+	for name := range values {
+		if !known[strings.ToLower(name)] {
+			return result, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+
+	for _, definition := range definitions {
+		value, ok := getParameterValue(values, definition.Name)
+		if !ok {
+			if definition.IsRequired() {
+				if required {
+					return result, fmt.Errorf("parameter %s is required", definition.Name)
+				}
+				continue
+			}
+			value = fmt.Sprintf("%v", definition.Default)
+		}
+
+		parsed, err := parseParameterValue(definition, value)
+		if err != nil {
+			return result, err
+		}
+		result[strings.ToLower(definition.Name)] = parsed
+	}
+
+	return result, nil
+}
+
+// parseParameterValue checks a value against the type of the parameter and normalizes it
+func parseParameterValue(definition *pipeline_component.PipelineParameter, value string) (string, error) {
+	switch definition.GetType() {
+	case pipeline_component.ParameterTypeBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("parameter %s needs to be true or false, got %s", definition.Name, value)
+		}
+		return strconv.FormatBool(parsed), nil
+	case pipeline_component.ParameterTypeInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("parameter %s needs to be a number, got %s", definition.Name, value)
+		}
+		return strconv.Itoa(parsed), nil
+	case pipeline_component.ParameterTypeChoice:
+		for _, allowed := range definition.Values {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("parameter %s needs to be one of %s, got %s", definition.Name, strings.Join(definition.Values, ", "), value)
+	default:
+		return value, nil
+	}
+}
+
+// setParameters makes the pipeline parameters available as ${{ parameters.<name> }}
+func setParameters(values map[string]string) error {
+	env := environment.Get()
+	vault := pipeline_vault.GetParameters()
+
+	vault.Clear()
+	for key, value := range values {
+		vault.Set(key, value)
+	}
+
+	if err := env.Register(vault); err != nil {
+		return err
+	}
+
+	return env.RefreshVault(vault.Name())
+}
+
+// replaceParameters replaces the ${{ parameters.<name> }} references of the given values, other
+// references are kept so they are resolved when the task runs
+func replaceParameters(source string, values map[string]string) string {
+	return parameterReferenceRegex.ReplaceAllStringFunc(source, func(reference string) string {
+		name := parameterReferenceRegex.FindStringSubmatch(reference)[1]
+		if value, ok := values[strings.ToLower(name)]; ok {
+			return value
+		}
+		return reference
+	})
+}
+
+func getParameterValue(values map[string]string, name string) (string, bool) {
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,201 @@
+package lanes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/icons"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/lanes/retry"
+
+	"gopkg.in/yaml.v3"
+)
+
+// planPipeline prints the execution order of a pipeline and what each worker would do with its
+// placeholders resolved, nothing is executed. Outputs of previous steps are not known at this
+// point so they are printed as placeholders
+func (automation *PipelineService) planPipeline(pipeline *pipeline_component.Pipeline, parameters map[string]string) error {
+	if err := resetOutputs(); err != nil {
+		notify.FromError(err, "There was an error registering the steps vault for pipeline %s", pipeline.Name)
+		return err
+	}
+
+	notify.InfoWithIcon(icons.IconClipboard, "Dry run of pipeline %s, nothing will be executed", pipeline.Name)
+	names := make([]string, 0)
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := parameters[name]
+		if environment.IsSecretKey(name) {
+			value = environment.MASK
+		}
+		notify.InfoIndentIcon(icons.IconKey, "parameter %s = %s", "  ", name, value)
+	}
+
+	if err := automation.planJobs(pipeline, pipeline.Jobs); err != nil {
+		return err
+	}
+	if len(pipeline.OnFailure) > 0 {
+		notify.InfoWithIcon(icons.IconClipboard, "onFailure jobs, these run when a job fails")
+		if err := automation.planJobs(pipeline, pipeline.OnFailure); err != nil {
+			return err
+		}
+	}
+	if len(pipeline.Finally) > 0 {
+		notify.InfoWithIcon(icons.IconClipboard, "finally jobs, these always run at the end")
+		if err := automation.planJobs(pipeline, pipeline.Finally); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planJobs prints the jobs and steps of a list of jobs in the order they run
+func (automation *PipelineService) planJobs(pipeline *pipeline_component.Pipeline, jobs []*pipeline_component.PipelineJob) error {
+	graph, err := buildExecutionGraph(jobs)
+	if err != nil {
+		notify.FromError(err, "There was an error building the jobs graph for pipeline %s", pipeline.Name)
+		return err
+	}
+
+	for jobIndex, job := range graph.Order() {
+		jobNumber := strconv.Itoa(jobIndex + 1)
+		if job.Disabled {
+			notify.InfoIndentIcon(icons.IconBlackSquare, "%s. job %s is disabled", "  ", jobNumber, job.Name)
+			continue
+		}
+
+		notify.InfoIndentIcon(icons.IconFolder, "%s. job %s", "  ", jobNumber, job.Name)
+		for _, line := range describeSettings(job.DependsOn, job.If, job.Timeout) {
+			notify.InfoIndentIcon(icons.IconRightArrow, "%s", "     ", line)
+		}
+
+		steps, err := buildExecutionGraph(job.Steps)
+		if err != nil {
+			notify.FromError(err, "There was an error building the steps graph for job %s in pipeline %s", job.Name, pipeline.Name)
+			return err
+		}
+
+		for stepIndex, step := range steps.Order() {
+			stepNumber := fmt.Sprintf("%s.%s", jobNumber, strconv.Itoa(stepIndex+1))
+			if step.Disabled {
+				notify.InfoIndentIcon(icons.IconBlackSquare, "%s. step %s is disabled", "    ", stepNumber, step.Name)
+				continue
+			}
+
+			notify.InfoIndentIcon(icons.IconPage, "%s. step %s (%s)", "    ", stepNumber, step.Name, step.Type.String())
+			settings := describeSettings(step.DependsOn, step.If, step.Timeout)
+			if policy, err := retry.NewPolicy(step); err == nil && policy.MaxAttempts > 1 {
+				settings = append(settings, fmt.Sprintf("retry up to %s attempts with %s backoff", strconv.Itoa(policy.MaxAttempts), policy.Backoff))
+			}
+			for _, line := range settings {
+				notify.InfoIndentIcon(icons.IconRightArrow, "%s", "       ", line)
+			}
+
+			lines, err := automation.plan(step)
+			if err != nil {
+				notify.FromError(err, "There was an error planning the task %s in job %s for pipeline %s", step.Name, job.Name, pipeline.Name)
+				return err
+			}
+			for _, line := range lines {
+				notify.InfoIndentIcon("", "%s", "         ", line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// plan asks the workers to describe the task, tasks handled by workers that cannot describe
+// themselves show their resolved inputs instead
+func (automation *PipelineService) plan(task *pipeline_component.PipelineTask) ([]string, error) {
+	env := environment.Get()
+	description := ""
+	planned := false
+	for _, worker := range automation.workers {
+		planner, ok := worker.New().(interfaces.PipelineWorkerPlanner)
+		if !ok {
+			continue
+		}
+
+		result := planner.Plan(task)
+		if result.State == entities.StateErrored {
+			return nil, result.Error
+		}
+		if result.State == entities.StateValid {
+			description = result.Output
+			planned = true
+		}
+	}
+
+	if !planned {
+		if len(task.Inputs) == 0 {
+			return []string{}, nil
+		}
+
+		content, err := yaml.Marshal(resolveInputs(task.Inputs))
+		if err != nil {
+			return nil, err
+		}
+		description = "inputs:\n" + strings.TrimRight(string(content), "\n")
+	}
+
+	return strings.Split(env.Mask(description), "\n"), nil
+}
+
+func describeSettings(dependsOn []string, condition string, timeout string) []string {
+	result := make([]string, 0)
+	if len(dependsOn) > 0 {
+		result = append(result, fmt.Sprintf("after %s", strings.Join(dependsOn, ", ")))
+	}
+	if condition != "" {
+		result = append(result, fmt.Sprintf("if %s", condition))
+	}
+	if timeout != "" {
+		result = append(result, fmt.Sprintf("timeout %s", timeout))
+	}
+
+	return result
+}
+
+// resolveInputs replaces the placeholders in all the string values of the inputs and masks the
+// values of keys that look like secrets
+func resolveInputs(value interface{}) interface{} {
+	env := environment.Get()
+
+	switch v := value.(type) {
+	case string:
+		return env.Replace(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		keys := make([]string, 0)
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if environment.IsSecretKey(key) {
+				result[key] = environment.MASK
+				continue
+			}
+			result[key] = resolveInputs(v[key])
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = resolveInputs(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,88 @@
+package lanes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/workers/keyvaultworker"
+	"github.com/cjlapao/locally-cli/vaults/keyvault_vault"
+	"github.com/cjlapao/locally-cli/vaults/pipeline_vault"
+	"github.com/cjlapao/locally-cli/vaults/terraform_vault"
+)
+
+// checkReferences resolves the inputs of every step of the pipeline before it runs and returns
+// all the placeholders that cannot be resolved, the steps and pipeline vaults and the values
+// written by the infrastructure and keyvault steps are only filled while running so their
+// placeholders are not checked
+func checkReferences(pipeline *pipeline_component.Pipeline) error {
+	ignored := []string{pipeline_vault.Get().Name(), pipeline_vault.GetStatus().Name()}
+	ignored = append(ignored, runtimeReferences(pipeline)...)
+	checker := environment.Get().NewReferenceChecker(ignored...)
+
+	for _, jobs := range [][]*pipeline_component.PipelineJob{pipeline.Jobs, pipeline.OnFailure, pipeline.Finally} {
+		for _, job := range jobs {
+			for _, step := range job.Steps {
+				path := fmt.Sprintf("%s.%s.%s", pipeline.Name, job.Name, step.Name)
+				checker.Check(pipeline.Source, fmt.Sprintf("%s.workingDirectory", path), step.WorkingDirectory)
+				checker.Check(pipeline.Source, fmt.Sprintf("%s.inputs", path), step.Inputs)
+				checker.Check(pipeline.Source, fmt.Sprintf("%s.body", path), step.Body)
+			}
+		}
+	}
+
+	return checker.Err()
+}
+
+// runtimeReferences returns the references filled by the steps of the pipeline, the outputs of
+// the stacks of the infrastructure steps and the secrets synced or set by the keyvault steps, the
+// whole vault is returned when the step does not name a single stack or keyvault
+func runtimeReferences(pipeline *pipeline_component.Pipeline) []string {
+	env := environment.Get()
+	result := make([]string, 0)
+
+	for _, jobs := range [][]*pipeline_component.PipelineJob{pipeline.Jobs, pipeline.OnFailure, pipeline.Finally} {
+		for _, job := range jobs {
+			for _, step := range job.Steps {
+				switch step.Type {
+				case pipeline_component.InfrastructureTask:
+					vault := terraform_vault.New().Name()
+					stack := env.Replace(inputString(step, "stackName"))
+					if stack == "" || strings.Contains(stack, environment.PREFIX) || inputBool(step, "buildDependencies") {
+						result = append(result, vault)
+					} else {
+						result = append(result, fmt.Sprintf("%s.%s", vault, common.EncodeName(stack)))
+					}
+				case pipeline_component.KeyvaultSyncTask:
+					if inputString(step, "command") == keyvaultworker.CommandDelete {
+						continue
+					}
+					vault := keyvault_vault.New().Name()
+					name := env.Replace(inputString(step, "name"))
+					if name == "" || strings.Contains(name, environment.PREFIX) {
+						result = append(result, vault)
+					} else {
+						result = append(result, fmt.Sprintf("%s.%s", vault, name))
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func inputString(step *pipeline_component.PipelineTask, key string) string {
+	if value, ok := step.Inputs[key].(string); ok {
+		return strings.TrimSpace(value)
+	}
+
+	return ""
+}
+
+func inputBool(step *pipeline_component.PipelineTask, key string) bool {
+	value, ok := step.Inputs[key].(bool)
+	return ok && value
+}
@@ -0,0 +1,120 @@
+package lanes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+func TestCheckReferences(t *testing.T) {
+	pipeline := &pipeline_component.Pipeline{
+		Name:   "deploy",
+		Source: "pipelines/deploy.yml",
+		Jobs: []*pipeline_component.PipelineJob{
+			{
+				Name: "build",
+				Steps: []*pipeline_component.PipelineTask{
+					{
+						Name:             "compile",
+						WorkingDirectory: "${{ global.missing_path }}",
+						Inputs: map[string]interface{}{
+							"command": "${{ global.missing_command }} --id ${{ uuid }}",
+							"token":   "${{ steps.login.outputs.token }}",
+							"port":    "${{ global.missing_port | default 8080 }}",
+						},
+					},
+				},
+			},
+		},
+		Finally: []*pipeline_component.PipelineJob{
+			{
+				Name: "cleanup",
+				Steps: []*pipeline_component.PipelineTask{
+					{Name: "notify", Body: "echo ${{ pipeline.status }} ${{ global.missing_channel }}"},
+				},
+			},
+		},
+	}
+
+	err := checkReferences(pipeline)
+	var referencesErr *environment.UnresolvedReferencesError
+	if !errors.As(err, &referencesErr) {
+		t.Fatalf("expected an unresolved references error, got %v", err)
+	}
+
+	want := []string{
+		"${{ global.missing_path }} in pipelines/deploy.yml at deploy.build.compile.workingDirectory",
+		"${{ global.missing_command }} in pipelines/deploy.yml at deploy.build.compile.inputs.command",
+		"${{ global.missing_channel }} in pipelines/deploy.yml at deploy.cleanup.notify.body",
+	}
+	if len(referencesErr.References) != len(want) {
+		t.Fatalf("expected %v references, got %v", len(want), err)
+	}
+	for i, reference := range referencesErr.References {
+		if reference.String() != want[i] {
+			t.Errorf("reference %v = %q, want %q", i, reference.String(), want[i])
+		}
+	}
+	if !strings.Contains(err.Error(), "found 3 unresolved references") {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+}
+
+func TestCheckReferences_RuntimeVaults(t *testing.T) {
+	pipeline := &pipeline_component.Pipeline{
+		Name:   "deploy",
+		Source: "pipelines/deploy.yml",
+		Jobs: []*pipeline_component.PipelineJob{
+			{
+				Name: "infrastructure",
+				Steps: []*pipeline_component.PipelineTask{
+					{Name: "apply", Type: pipeline_component.InfrastructureTask, Inputs: map[string]interface{}{"command": "up", "stackName": "Api Database"}},
+					{Name: "secrets", Type: pipeline_component.KeyvaultSyncTask, Inputs: map[string]interface{}{"keyvaultUrl": "https://kv", "name": "global"}},
+					{Name: "cleanup", Type: pipeline_component.KeyvaultSyncTask, Inputs: map[string]interface{}{"keyvaultUrl": "https://kv", "name": "other", "command": "delete"}},
+				},
+			},
+			{
+				Name: "migrate",
+				Steps: []*pipeline_component.PipelineTask{
+					{
+						Name: "seed",
+						Inputs: map[string]interface{}{
+							"connectionString": "${{ terraform.api_database.connection_string }}",
+							"password":         "${{ keyvault.global.sql-password }}",
+							"cache":            "${{ terraform.cache.connection_string }}",
+							"token":            "${{ keyvault.other.token }}",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := checkReferences(pipeline)
+	var referencesErr *environment.UnresolvedReferencesError
+	if !errors.As(err, &referencesErr) {
+		t.Fatalf("expected an unresolved references error, got %v", err)
+	}
+
+	want := []string{
+		"${{ terraform.cache.connection_string }} in pipelines/deploy.yml at deploy.migrate.seed.inputs.cache",
+		"${{ keyvault.other.token }} in pipelines/deploy.yml at deploy.migrate.seed.inputs.token",
+	}
+	if len(referencesErr.References) != len(want) {
+		t.Fatalf("expected %v references, got %v", len(want), err)
+	}
+	for i, reference := range referencesErr.References {
+		if reference.String() != want[i] {
+			t.Errorf("reference %v = %q, want %q", i, reference.String(), want[i])
+		}
+	}
+
+	pipeline.Jobs[0].Steps[0].Inputs["buildDependencies"] = true
+	pipeline.Jobs[1].Steps[0].Inputs["token"] = "${{ keyvault.global.token }}"
+	if err := checkReferences(pipeline); err != nil {
+		t.Errorf("expected the whole terraform vault to be ignored when building dependencies, got %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package lanes
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+
+	"github.com/cjlapao/common-go/helper"
+)
+
+const (
+	ReportFormatJUnit = "junit"
+	ReportFormatJson  = "json"
+)
+
+const (
+	ReportKindRun      = "run"
+	ReportKindValidate = "validate"
+)
+
+// PipelineReportTarget is a report requested with --report <format>=<path>
+type PipelineReportTarget struct {
+	Format string
+	Path   string
+}
+
+// PipelineReport collects the result of every task that was run or validated so it can be
+// written as a JUnit or JSON report for CI systems
+type PipelineReport struct {
+	Suites []*PipelineReportSuite `json:"suites"`
+	mutex  sync.Mutex
+}
+
+// PipelineReportSuite is the result of running or validating a single pipeline
+type PipelineReportSuite struct {
+	Pipeline   string                `json:"pipeline"`
+	Kind       string                `json:"kind"`
+	RunID      string                `json:"runId,omitempty"`
+	State      string                `json:"state"`
+	Error      string                `json:"error,omitempty"`
+	StartedAt  time.Time             `json:"startedAt"`
+	FinishedAt time.Time             `json:"finishedAt"`
+	Duration   float64               `json:"duration"`
+	Tasks      []*PipelineTaskReport `json:"tasks"`
+}
+
+type PipelineTaskReport struct {
+	Job       string  `json:"job"`
+	Step      string  `json:"step,omitempty"`
+	State     string  `json:"state"`
+	ErrorCode string  `json:"errorCode,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	Duration  float64 `json:"duration"`
+	Output    string  `json:"output,omitempty"`
+}
+
+// ParseReportTarget reads a <format>=<path> report value
+func ParseReportTarget(value string) (*PipelineReportTarget, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+		return nil, fmt.Errorf("invalid report %s, it needs to be <format>=<path>", value)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(parts[0]))
+	if format != ReportFormatJUnit && format != ReportFormatJson {
+		return nil, fmt.Errorf("invalid report format %s, it needs to be one of %s or %s", parts[0], ReportFormatJUnit, ReportFormatJson)
+	}
+
+	return &PipelineReportTarget{
+		Format: format,
+		Path:   strings.TrimSpace(parts[1]),
+	}, nil
+}
+
+func newPipelineReport() *PipelineReport {
+	return &PipelineReport{
+		Suites: make([]*PipelineReportSuite, 0),
+	}
+}
+
+func newPipelineReportSuite(pipeline string, kind string) *PipelineReportSuite {
+	return &PipelineReportSuite{
+		Pipeline:  pipeline,
+		Kind:      kind,
+		State:     RunStateRunning,
+		StartedAt: time.Now(),
+		Tasks:     make([]*PipelineTaskReport, 0),
+	}
+}
+
+// addTask adds the result of a task, the output is masked as reports usually end up as build
+// artifacts
+func (suite *PipelineReportSuite) addTask(job, step string, state entities.PipelineWorkerResultState, errorCode string, err error, duration time.Duration, output string) {
+	task := PipelineTaskReport{
+		Job:       job,
+		Step:      step,
+		State:     state.String(),
+		ErrorCode: errorCode,
+		Duration:  duration.Seconds(),
+		Output:    environment.Get().Mask(output),
+	}
+	if err != nil {
+		task.Error = environment.Get().Mask(err.Error())
+	}
+
+	suite.Tasks = append(suite.Tasks, &task)
+}
+
+// hasErrors returns true if any of the tasks of the suite errored
+func (suite *PipelineReportSuite) hasErrors() bool {
+	for _, task := range suite.Tasks {
+		if task.State == entities.StateErrored.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (suite *PipelineReportSuite) finish(err error) {
+	suite.FinishedAt = time.Now()
+	suite.Duration = suite.FinishedAt.Sub(suite.StartedAt).Seconds()
+	if err != nil {
+		suite.State = RunStateFailed
+		suite.Error = environment.Get().Mask(err.Error())
+	} else {
+		suite.State = RunStateSucceeded
+	}
+}
+
+func (report *PipelineReport) add(suite *PipelineReportSuite) {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	report.Suites = append(report.Suites, suite)
+}
+
+// addRun adds the tasks of a finished pipeline run to the report
+func (report *PipelineReport) addRun(record *PipelineRunRecord) {
+	suite := newPipelineReportSuite(record.Pipeline, ReportKindRun)
+	suite.RunID = record.ID
+	suite.StartedAt = record.StartedAt
+
+	record.mutex.Lock()
+	for _, task := range record.Tasks {
+		var state entities.PipelineWorkerResultState
+		state.FromString(task.State)
+		var err error
+		if task.Error != "" {
+			err = fmt.Errorf("%s", task.Error)
+		}
+		suite.addTask(task.Job, task.Step, state, task.ErrorCode, err, task.Duration(), task.Output)
+	}
+	var err error
+	if record.Error != "" {
+		err = fmt.Errorf("%s", record.Error)
+	}
+	record.mutex.Unlock()
+
+	// failures outside of a task, like an invalid job condition, are reported on the pipeline
+	if err != nil && !suite.hasErrors() {
+		suite.addTask(record.Pipeline, "", entities.StateErrored, "", err, record.Duration(), "")
+	}
+
+	suite.finish(err)
+	suite.FinishedAt = record.FinishedAt
+	suite.Duration = record.Duration().Seconds()
+	report.add(suite)
+}
+
+// Write saves the report in the format of the target
+func (report *PipelineReport) Write(target *PipelineReportTarget) error {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	var content []byte
+	var err error
+	switch target.Format {
+	case ReportFormatJson:
+		content, err = json.MarshalIndent(report, "", "  ")
+	case ReportFormatJUnit:
+		content, err = report.junit()
+	default:
+		err = fmt.Errorf("invalid report format %s", target.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	folder := filepath.Dir(target.Path)
+	if !helper.DirectoryExists(folder) {
+		if err := os.MkdirAll(folder, fs.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return helper.WriteToFile(string(content), target.Path)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// junit converts the report to the JUnit XML format, every pipeline is a test suite and every
+// task a test case named after its step with the pipeline and job as class name
+func (report *PipelineReport) junit() ([]byte, error) {
+	result := junitTestSuites{
+		Suites: make([]junitTestSuite, 0),
+	}
+
+	total := 0.0
+	for _, suite := range report.Suites {
+		name := suite.Pipeline
+		if suite.Kind == ReportKindValidate {
+			name = fmt.Sprintf("%s (validate)", suite.Pipeline)
+		}
+
+		junitSuite := junitTestSuite{
+			Name:      name,
+			Time:      formatSeconds(suite.Duration),
+			Timestamp: suite.StartedAt.Format("2006-01-02T15:04:05"),
+			Cases:     make([]junitTestCase, 0),
+		}
+
+		for _, task := range suite.Tasks {
+			testCase := junitTestCase{
+				Name:      task.Step,
+				ClassName: fmt.Sprintf("%s.%s", suite.Pipeline, task.Job),
+				Time:      formatSeconds(task.Duration),
+				SystemOut: task.Output,
+			}
+			if task.Step == "" {
+				testCase.Name = task.Job
+				testCase.ClassName = suite.Pipeline
+			}
+
+			switch task.State {
+			case entities.StateErrored.String():
+				testCase.Failure = &junitFailure{
+					Message: task.Error,
+					Type:    task.ErrorCode,
+					Content: task.Error,
+				}
+				junitSuite.Failures += 1
+			case entities.StateIgnored.String():
+				testCase.Skipped = &struct{}{}
+				junitSuite.Skipped += 1
+			}
+
+			junitSuite.Cases = append(junitSuite.Cases, testCase)
+		}
+
+		junitSuite.Tests = len(junitSuite.Cases)
+		result.Tests += junitSuite.Tests
+		result.Failures += junitSuite.Failures
+		result.Skipped += junitSuite.Skipped
+		total += suite.Duration
+		result.Suites = append(result.Suites, junitSuite)
+	}
+	result.Time = formatSeconds(total)
+
+	content, err := xml.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+func formatSeconds(value float64) string {
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
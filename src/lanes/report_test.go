@@ -0,0 +1,92 @@
+package lanes
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestParseReportTarget(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    PipelineReportTarget
+		wantErr bool
+	}{
+		{"junit=out/report.xml", PipelineReportTarget{Format: ReportFormatJUnit, Path: "out/report.xml"}, false},
+		{"JSON=report.json", PipelineReportTarget{Format: ReportFormatJson, Path: "report.json"}, false},
+		{"html=report.html", PipelineReportTarget{}, true},
+		{"junit", PipelineReportTarget{}, true},
+		{"junit=", PipelineReportTarget{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseReportTarget(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReportTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("ParseReportTarget() = %v, want %v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineReport_JUnit(t *testing.T) {
+	started := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	record := &PipelineRunRecord{
+		ID:         "run-1",
+		Pipeline:   "seed",
+		Error:      "step migrate failed",
+		StartedAt:  started,
+		FinishedAt: started.Add(3 * time.Second),
+		Tasks: []*PipelineTaskRecord{
+			{Job: "database", Step: "clone", State: entities.StateExecuted.String(), StartedAt: started, FinishedAt: started.Add(time.Second), Output: "cloned"},
+			{Job: "database", Step: "migrate", State: entities.StateErrored.String(), ErrorCode: "1", Error: "step migrate failed", StartedAt: started, FinishedAt: started.Add(2 * time.Second)},
+			{Job: "database", Step: "seed", State: entities.StateIgnored.String(), StartedAt: started, FinishedAt: started},
+		},
+	}
+
+	report := newPipelineReport()
+	report.addRun(record)
+
+	content, err := report.junit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result junitTestSuites
+	if err := xml.Unmarshal(content, &result); err != nil {
+		t.Fatalf("invalid junit xml, %v", err)
+	}
+
+	if result.Tests != 3 || result.Failures != 1 || result.Skipped != 1 {
+		t.Errorf("unexpected totals tests=%v failures=%v skipped=%v", result.Tests, result.Failures, result.Skipped)
+	}
+	cases := result.Suites[0].Cases
+	if cases[0].ClassName != "seed.database" || cases[0].Name != "clone" || cases[0].Time != "1.000" || cases[0].SystemOut != "cloned" {
+		t.Errorf("unexpected test case %+v", cases[0])
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Type != "1" || cases[1].Failure.Message != "step migrate failed" {
+		t.Errorf("expected a failure for migrate, got %+v", cases[1])
+	}
+}
+
+func TestPipelineReport_PipelineFailure(t *testing.T) {
+	record := &PipelineRunRecord{
+		ID:       "run-1",
+		Pipeline: "seed",
+		Error:    "invalid condition",
+		Tasks:    []*PipelineTaskRecord{},
+	}
+
+	report := newPipelineReport()
+	report.addRun(record)
+
+	tasks := report.Suites[0].Tasks
+	if len(tasks) != 1 || tasks[0].State != entities.StateErrored.String() || tasks[0].Job != "seed" {
+		t.Errorf("expected the pipeline failure to be reported, got %+v", tasks)
+	}
+}
@@ -1,55 +1,212 @@
 package retry
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/notifications"
 )
 
-func RetryRun(task *pipeline_component.PipelineTask, funcToExecute func(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult, retryCount, waitFor int) entities.PipelineWorkerResult {
-	var result entities.PipelineWorkerResult
-	notify := notifications.Get()
+const (
+	BackoffFixed       = "fixed"
+	BackoffExponential = "exponential"
+	BackoffJitter      = "jitter"
+)
+
+// Policy is the parsed retry block of a task
+type Policy struct {
+	MaxAttempts int
+	Backoff     string
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	StatusCodes []string
+	ExitCodes   []string
+	Errors      []*regexp.Regexp
+}
+
+// NewPolicy builds the retry policy of a task, tasks without a retry block only run once unless
+// they still use the deprecated retryCountOnFailure or the retryCount and waitFor inputs
+func NewPolicy(task *pipeline_component.PipelineTask) (*Policy, error) {
+	policy := Policy{
+		MaxAttempts: 1,
+		Backoff:     BackoffFixed,
+		Delay:       time.Duration(common.DEFAULT_WAITING_FOR_SECONDS) * time.Second,
+	}
+
+	if task.Retry == nil {
+		if task.RetryCountOnFailure > 0 {
+			policy.MaxAttempts = task.RetryCountOnFailure + 1
+		}
+		if retryCount := getIntInput(task, "retryCount"); retryCount > 0 {
+			policy.MaxAttempts = retryCount
+		}
+		policy.Delay = time.Duration(getIntInput(task, "waitFor")) * time.Second
+
+		return &policy, nil
+	}
 
-	waiting := time.Second * 0
+	policy.MaxAttempts = task.Retry.MaxAttempts
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = common.DEFAULT_RETRY_COUNT
+	}
+	if policy.MaxAttempts < 0 {
+		return nil, fmt.Errorf("maxAttempts cannot be negative")
+	}
 
-	// if retryCount == 0 {
-	// 	retryCount = common.DEFAULT_RETRY_COUNT
-	// }
-	if waitFor > 0 {
-		waiting = time.Second * time.Duration(waitFor)
+	if task.Retry.Backoff != "" {
+		policy.Backoff = strings.ToLower(task.Retry.Backoff)
+	}
+	switch policy.Backoff {
+	case BackoffFixed, BackoffExponential, BackoffJitter:
+	default:
+		return nil, fmt.Errorf("invalid backoff %s, it needs to be one of %s, %s or %s", task.Retry.Backoff, BackoffFixed, BackoffExponential, BackoffJitter)
 	}
 
-	if funcToExecute == nil {
-		result.State = entities.StateIgnored
-		result.ErrorCode = "100"
-		result.Error = errors.New("no task to execute")
+	if task.Retry.Delay != "" {
+		delay, err := common.ParseDuration(task.Retry.Delay)
+		if err != nil {
+			return nil, err
+		}
+		policy.Delay = delay
+	}
 
-		return result
+	if task.Retry.MaxDelay != "" {
+		maxDelay, err := common.ParseDuration(task.Retry.MaxDelay)
+		if err != nil {
+			return nil, err
+		}
+		policy.MaxDelay = maxDelay
+	}
+
+	if task.Retry.RetryOn != nil {
+		for _, code := range task.Retry.RetryOn.StatusCodes {
+			policy.StatusCodes = append(policy.StatusCodes, strconv.Itoa(code))
+		}
+		for _, code := range task.Retry.RetryOn.ExitCodes {
+			policy.ExitCodes = append(policy.ExitCodes, strconv.Itoa(code))
+		}
+		for _, expression := range task.Retry.RetryOn.Errors {
+			re, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid error expression %s, %s", expression, err.Error())
+			}
+			policy.Errors = append(policy.Errors, re)
+		}
+	}
+
+	return &policy, nil
+}
+
+// ShouldRetry checks if a failed result matches the retryOn conditions, without conditions
+// any failure is retried
+func (policy *Policy) ShouldRetry(result entities.PipelineWorkerResult) bool {
+	if result.Error == nil {
+		return false
+	}
+
+	if len(policy.StatusCodes) == 0 && len(policy.ExitCodes) == 0 && len(policy.Errors) == 0 {
+		return true
+	}
+
+	for _, code := range policy.StatusCodes {
+		if result.StatusCode == code {
+			return true
+		}
 	}
 
+	for _, code := range policy.ExitCodes {
+		if result.ExitCode == code {
+			return true
+		}
+	}
+
+	for _, re := range policy.Errors {
+		if re.MatchString(result.Error.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WaitFor returns how long to wait before the next attempt, attempt starts at 1
+func (policy *Policy) WaitFor(attempt int) time.Duration {
+	delay := policy.Delay
+	if policy.Backoff == BackoffExponential || policy.Backoff == BackoffJitter {
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+				break
+			}
+		}
+	}
+
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	if policy.Backoff == BackoffJitter && delay > 0 {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+
+	return delay
+}
+
+// Run executes the function until it succeeds, the failure cannot be retried, the attempts run
+// out or the context is done
+func Run(ctx context.Context, policy *Policy, name string, funcToExecute func(ctx context.Context) entities.PipelineWorkerResult) entities.PipelineWorkerResult {
+	notify := notifications.Get()
+
+	attempt := 1
 	for {
-		result = funcToExecute(task)
-		if result.Error == nil {
+		result := funcToExecute(ctx)
+		if result.Error == nil || ctx.Err() != nil {
 			return result
 		}
 
-		retryCount -= 1
-
-		if retryCount <= 0 {
-			notify.Error("Exceeded maximum number of retries, returning bad result")
-			break
+		if !policy.ShouldRetry(result) {
+			if policy.MaxAttempts > 1 {
+				notify.Debug("Failure of task %s does not match the retry conditions, not retrying", name)
+			}
+			return result
 		}
 
-		notify.Info("Will retry for %s more time(s)", fmt.Sprintf("%v", retryCount))
+		if attempt >= policy.MaxAttempts {
+			if policy.MaxAttempts > 1 {
+				notify.Error("Task %s exceeded the maximum number of %s attempts", name, strconv.Itoa(policy.MaxAttempts))
+			}
+			return result
+		}
 
-		if waiting.Seconds() > 0 {
-			notify.Info("Waiting for %s before next retry", waiting.String())
-			time.Sleep(waiting)
+		waiting := policy.WaitFor(attempt)
+		notify.Info("Attempt %s of %s for task %s failed, retrying in %s", strconv.Itoa(attempt), strconv.Itoa(policy.MaxAttempts), name, waiting.String())
+		select {
+		case <-time.After(waiting):
+		case <-ctx.Done():
+			return result
 		}
+
+		attempt += 1
+	}
+}
+
+func getIntInput(task *pipeline_component.PipelineTask, name string) int {
+	value, ok := task.Inputs[name]
+	if !ok || value == nil {
+		return 0
+	}
+
+	result, err := strconv.Atoi(fmt.Sprintf("%v", value))
+	if err != nil {
+		return 0
 	}
 
 	return result
@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestNewPolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		task            pipeline_component.PipelineTask
+		wantMaxAttempts int
+		wantErr         bool
+	}{
+		{"no retry runs once", pipeline_component.PipelineTask{}, 1, false},
+		{"retry count on failure", pipeline_component.PipelineTask{RetryCountOnFailure: 2}, 3, false},
+		{"retry count input", pipeline_component.PipelineTask{Inputs: map[string]interface{}{"retryCount": 4}}, 4, false},
+		{"retry block defaults", pipeline_component.PipelineTask{Retry: &pipeline_component.PipelineRetry{}}, 3, false},
+		{"invalid backoff", pipeline_component.PipelineTask{Retry: &pipeline_component.PipelineRetry{Backoff: "linear"}}, 0, true},
+		{"invalid max delay", pipeline_component.PipelineTask{Retry: &pipeline_component.PipelineRetry{MaxDelay: "soon"}}, 0, true},
+		{"invalid error expression", pipeline_component.PipelineTask{Retry: &pipeline_component.PipelineRetry{RetryOn: &pipeline_component.PipelineRetryOn{Errors: []string{"("}}}}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPolicy(&tt.task)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.MaxAttempts != tt.wantMaxAttempts {
+				t.Errorf("NewPolicy() MaxAttempts = %v, want %v", got.MaxAttempts, tt.wantMaxAttempts)
+			}
+		})
+	}
+}
+
+func TestPolicy_ShouldRetry(t *testing.T) {
+	policy, err := NewPolicy(&pipeline_component.PipelineTask{
+		Retry: &pipeline_component.PipelineRetry{
+			RetryOn: &pipeline_component.PipelineRetryOn{
+				StatusCodes: []int{502, 503},
+				ExitCodes:   []int{75},
+				Errors:      []string{"(?i)connection refused"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		result entities.PipelineWorkerResult
+		want   bool
+	}{
+		{"success", entities.PipelineWorkerResult{}, false},
+		{"matching status code", entities.PipelineWorkerResult{Error: errors.New("failed"), StatusCode: "503"}, true},
+		{"other status code", entities.PipelineWorkerResult{Error: errors.New("failed"), StatusCode: "404"}, false},
+		{"matching exit code", entities.PipelineWorkerResult{Error: errors.New("failed"), ExitCode: "75"}, true},
+		{"matching error", entities.PipelineWorkerResult{Error: errors.New("dial tcp: Connection Refused")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.ShouldRetry(tt.result); got != tt.want {
+				t.Errorf("ShouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_WaitFor(t *testing.T) {
+	policy := Policy{Backoff: BackoffExponential, Delay: time.Second, MaxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := policy.WaitFor(i + 1); got != expected {
+			t.Errorf("WaitFor(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+
+	policy.Backoff = BackoffJitter
+	for attempt := 1; attempt <= 5; attempt++ {
+		if got := policy.WaitFor(attempt); got < 0 || got > policy.MaxDelay {
+			t.Errorf("WaitFor(%d) = %v, want between 0 and %v", attempt, got, policy.MaxDelay)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, Backoff: BackoffFixed}
+
+	attempts := 0
+	result := Run(context.Background(), &policy, "test", func(ctx context.Context) entities.PipelineWorkerResult {
+		attempts += 1
+		if attempts < 2 {
+			return entities.NewPipelineWorkerResultFromError("500", errors.New("failed"))
+		}
+		return entities.PipelineWorkerResult{State: entities.StateExecuted}
+	})
+	if result.Error != nil || attempts != 2 {
+		t.Errorf("Run() = %v after %d attempts, want success after 2 attempts", result.Error, attempts)
+	}
+
+	attempts = 0
+	result = Run(context.Background(), &policy, "test", func(ctx context.Context) entities.PipelineWorkerResult {
+		attempts += 1
+		return entities.NewPipelineWorkerResultFromError("500", errors.New("failed"))
+	})
+	if result.Error == nil || attempts != 3 {
+		t.Errorf("Run() = %v after %d attempts, want an error after 3 attempts", result.Error, attempts)
+	}
+}
@@ -0,0 +1,138 @@
+package lanes
+
+import (
+	"sync"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+// Some workers change process wide state (working folder, os arguments or rely on the
+// global notifications to detect errors), these need to run on their own
+var exclusiveTaskTypes = map[pipeline_component.PipelineTaskType]bool{
+	pipeline_component.InfrastructureTask: true,
+	pipeline_component.DockerTask:         true,
+	pipeline_component.ProxyTask:          true,
+}
+
+type PipelineRunOptions struct {
+	MaxParallel int
+	ResumeFrom  string
+	DryRun      bool
+	Strict      bool
+	Parameters  map[string]string
+}
+
+// pipelineRun holds the state shared by all the jobs and steps of a single pipeline execution
+type pipelineRun struct {
+	pipeline  *pipeline_component.Pipeline
+	options   *PipelineRunOptions
+	status    runStatus
+	record    *PipelineRunRecord
+	previous  *PipelineRunRecord
+	cleanup   bool
+	slots     chan struct{}
+	exclusive sync.RWMutex
+}
+
+// runStatus keeps the first error of a pipeline or a job, this is what the success() and
+// failure() conditions look at, the job and step that failed are kept for the cleanup jobs
+type runStatus struct {
+	mutex sync.Mutex
+	err   error
+	job   string
+	step  string
+}
+
+func (status *runStatus) fail(err error) {
+	status.failTask("", "", err)
+}
+
+func (status *runStatus) failTask(job string, step string, err error) {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+
+	if status.err == nil {
+		status.err = err
+		status.job = job
+		status.step = step
+	}
+}
+
+// failure returns the job, the step and the error of the first failure
+func (status *runStatus) failure() (string, string, error) {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+
+	return status.job, status.step, status.err
+}
+
+func (status *runStatus) failed() bool {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+
+	return status.err != nil
+}
+
+func (status *runStatus) firstError() error {
+	status.mutex.Lock()
+	defer status.mutex.Unlock()
+
+	return status.err
+}
+
+func newPipelineRun(pipeline *pipeline_component.Pipeline, options *PipelineRunOptions, previous *PipelineRunRecord) *pipelineRun {
+	run := pipelineRun{
+		pipeline: pipeline,
+		options:  options,
+		record:   newPipelineRunRecord(pipeline.Name),
+		previous: previous,
+		slots:    make(chan struct{}, options.MaxParallel),
+	}
+
+	if previous != nil {
+		run.record.ResumedFrom = previous.ID
+	}
+
+	return &run
+}
+
+// succeededBefore returns the record of the task if it succeeded in the run being resumed
+func (run *pipelineRun) succeededBefore(job *pipeline_component.PipelineJob, task *pipeline_component.PipelineTask) *PipelineTaskRecord {
+	// cleanup jobs always run again as they tear down what the resumed run starts
+	if run.previous == nil || run.cleanup {
+		return nil
+	}
+
+	previous := run.previous.GetTask(job.Name, task.Name)
+	if previous == nil || previous.State != entities.StateExecuted.String() {
+		return nil
+	}
+
+	// secrets are not saved in the history so a task with masked outputs runs again to publish
+	// its real outputs
+	if previous.isMasked() {
+		notify.Info("Step %s in job %s has outputs with secrets that were not saved, running it again", task.Name, job.Name)
+		return nil
+	}
+
+	return previous
+}
+
+func (run *pipelineRun) acquire(task *pipeline_component.PipelineTask) {
+	run.slots <- struct{}{}
+	if exclusiveTaskTypes[task.Type] {
+		run.exclusive.Lock()
+	} else {
+		run.exclusive.RLock()
+	}
+}
+
+func (run *pipelineRun) release(task *pipeline_component.PipelineTask) {
+	if exclusiveTaskTypes[task.Type] {
+		run.exclusive.Unlock()
+	} else {
+		run.exclusive.RUnlock()
+	}
+	<-run.slots
+}
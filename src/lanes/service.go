@@ -1,14 +1,18 @@
 package lanes
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/configuration"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/lanes/retry"
 	"github.com/cjlapao/locally-cli/lanes/workers/bashworker"
 	"github.com/cjlapao/locally-cli/lanes/workers/curlworker"
 	"github.com/cjlapao/locally-cli/lanes/workers/dockerworker"
@@ -18,18 +22,25 @@ import (
 	"github.com/cjlapao/locally-cli/lanes/workers/infrastructureworker"
 	"github.com/cjlapao/locally-cli/lanes/workers/keyvaultworker"
 	"github.com/cjlapao/locally-cli/lanes/workers/npmworker"
+	"github.com/cjlapao/locally-cli/lanes/workers/pluginworker"
+	"github.com/cjlapao/locally-cli/lanes/workers/proxyworker"
 	"github.com/cjlapao/locally-cli/lanes/workers/sqlworker"
+	"github.com/cjlapao/locally-cli/lanes/workers/templateworker"
+	"github.com/cjlapao/locally-cli/lanes/workers/waitworker"
+	"github.com/cjlapao/locally-cli/lanes/workers/webclientmanifestworker"
 )
 
 var globalAutomationService *PipelineService
 
 type PipelineService struct {
 	workers []interfaces.PipelineWorker
+	report  *PipelineReport
 }
 
 func New() *PipelineService {
 	svc := PipelineService{
 		workers: make([]interfaces.PipelineWorker, 0),
+		report:  newPipelineReport(),
 	}
 
 	svc.registerWorker(sqlworker.SqlPipelineWorker{})
@@ -42,6 +53,11 @@ func New() *PipelineService {
 	svc.registerWorker(dockerworker.DockerPipelineWorker{})
 	svc.registerWorker(dotnetworker.DotnetPipelineWorker{})
 	svc.registerWorker(npmworker.NpmPipelineWorker{})
+	svc.registerWorker(proxyworker.ProxyPipelineWorker{})
+	svc.registerWorker(webclientmanifestworker.WebClientManifestPipelineWorker{})
+	svc.registerWorker(waitworker.WaitPipelineWorker{})
+	svc.registerWorker(templateworker.TemplatePipelineWorker{})
+	svc.registerWorker(pluginworker.PluginPipelineWorker{})
 	return &svc
 }
 
@@ -57,38 +73,71 @@ func (pipeline *PipelineService) registerWorker(worker interfaces.PipelineWorker
 	pipeline.workers = append(pipeline.workers, worker)
 }
 
-func (pipeline *PipelineService) execute(task *pipeline_component.PipelineTask) error {
-	executed := false
+func (pipeline *PipelineService) execute(ctx context.Context, task *pipeline_component.PipelineTask) (entities.PipelineWorkerResult, error) {
+	executed := entities.PipelineWorkerResult{
+		State: entities.StateIgnored,
+	}
 	for _, worker := range pipeline.workers {
 		executer := worker.New()
-		result := executer.Run(task)
+		result := runWorker(ctx, executer, task)
 		if result.State == entities.StateErrored {
-			return result.Error
+			if result.Error == nil {
+				result.Error = fmt.Errorf("worker %s failed to execute task %s", executer.Name(), task.Name)
+			}
+			return result, result.Error
 		}
 		if result.State == entities.StateExecuted {
-			executed = true
+			executed = result
 		}
 	}
 
-	if !executed {
+	if executed.State != entities.StateExecuted {
 		notify.Debug("Task %s was not executed", task.Name)
 	}
 
-	return nil
+	return executed, nil
 }
 
-func (pipeline *PipelineService) validate(task *pipeline_component.PipelineTask) bool {
-	valid := true
+// validate asks the workers to validate the task, a task that no worker picks up is an error as
+// it would silently do nothing when running
+func (pipeline *PipelineService) validate(task *pipeline_component.PipelineTask) error {
+	var err error
+	handled := false
 	for _, worker := range pipeline.workers {
 		executer := worker.New()
 		result := executer.Validate(task)
+		if result.State != entities.StateIgnored {
+			handled = true
+		}
 		if result.State != entities.StateValid && result.State != entities.StateIgnored {
-			valid = false
 			notify.Error(result.String())
+			if err == nil {
+				err = result.Error
+			}
+			if err == nil {
+				err = fmt.Errorf("worker %s could not validate the task", executer.Name())
+			}
 		}
 	}
 
-	return valid
+	if !handled {
+		err = fmt.Errorf("there is no worker for tasks of type %s", task.Type.String())
+		notify.Error(err.Error())
+	}
+
+	return err
+}
+
+// WriteReports writes the tasks run or validated so far to the given report files
+func (pipeline *PipelineService) WriteReports(targets []*PipelineReportTarget) error {
+	for _, target := range targets {
+		if err := pipeline.report.Write(target); err != nil {
+			return fmt.Errorf("could not write the %s report %s, %s", target.Format, target.Path, err.Error())
+		}
+		notify.Info("Wrote the %s report to %s", target.Format, target.Path)
+	}
+
+	return nil
 }
 
 func (pipeline *PipelineService) GetPipelines(name string, buildDependencies bool) []*pipeline_component.Pipeline {
@@ -109,7 +158,32 @@ func (pipeline *PipelineService) GetPipelines(name string, buildDependencies boo
 	return result
 }
 
-func (automation *PipelineService) Run(name string) error {
+func (automation *PipelineService) Run(name string, options *PipelineRunOptions) error {
+	if options == nil {
+		options = &PipelineRunOptions{}
+	}
+	if options.MaxParallel <= 0 {
+		options.MaxParallel = 1
+	}
+
+	var previous *PipelineRunRecord
+	if options.ResumeFrom != "" {
+		record, err := automation.GetRun(options.ResumeFrom)
+		if err != nil {
+			notify.FromError(err, "Error resuming the run %s", options.ResumeFrom)
+			return err
+		}
+		if !strings.EqualFold(record.Pipeline, name) {
+			err := fmt.Errorf("run %s belongs to pipeline %s and not to %s", record.ID, record.Pipeline, name)
+			notify.FromError(err, "Error resuming the run %s", options.ResumeFrom)
+			return err
+		}
+		previous = record
+		if len(options.Parameters) == 0 {
+			options.Parameters = record.Parameters
+		}
+	}
+
 	pipelines := automation.GetPipelines(name, true)
 
 	if len(pipelines) == 0 {
@@ -118,33 +192,72 @@ func (automation *PipelineService) Run(name string) error {
 		return err
 	}
 
+	ctx := common.ShutdownContext()
+	done := common.StartCancellableOperation()
+	defer done()
+
 	for _, pipeline := range pipelines {
+		if ctx.Err() != nil {
+			return stoppedError(ctx, "pipeline", pipeline.Name)
+		}
 		if pipeline.Disabled {
 			notify.Info("Pipeline %s is disabled, continuing", pipeline.Name)
 			continue
 		}
-		notify.Wrench("Starting to run the pipeline %s", pipeline.Name)
-		for _, job := range pipeline.Jobs {
-			if job.Disabled {
-				notify.Info("Job %s for pipeline %s is disabled, continuing", job.Name, pipeline.Name)
-				continue
-			}
-			if common.IsVerbose() {
-				notify.Wrench("Starting to execute job %s for pipeline %s", job.Name, pipeline.Name)
+
+		pipeline, parameters, err := automation.preparePipeline(pipeline, options.Parameters, true)
+		if err != nil {
+			notify.FromError(err, "There was an error preparing the pipeline %s", pipeline.Name)
+			return err
+		}
+		if err := setParameters(parameters); err != nil {
+			notify.FromError(err, "There was an error registering the parameters vault for pipeline %s", pipeline.Name)
+			return err
+		}
+
+		shouldRunPipeline, err := shouldRun(pipeline.If, &runStatus{})
+		if err != nil {
+			notify.FromError(err, "There was an error evaluating the condition of pipeline %s", pipeline.Name)
+			return err
+		}
+		if !shouldRunPipeline {
+			notify.Info("Pipeline %s condition %s was not met, skipping", pipeline.Name, pipeline.If)
+			continue
+		}
+
+		if options.Strict && !options.DryRun {
+			if err := checkReferences(pipeline); err != nil {
+				notify.Error("There are unresolved references in the pipeline %s, %s", pipeline.Name, err.Error())
+				return err
 			}
-			for _, step := range job.Steps {
-				if step.Disabled {
-					notify.Info("Step %s in bob %s for pipeline %s is disabled, continuing", step.Name, job.Name, pipeline.Name)
-					continue
-				}
-				if common.IsVerbose() {
-					notify.Wrench("Starting to execute task %s in job %s for pipeline %s", step.Name, job.Name, pipeline.Name)
-				}
-				if err := automation.execute(step); err != nil {
-					notify.FromError(err, "There was an error executing the task %s in job %s for pipeline %s", step.Name, job.Name, pipeline.Name)
-					return err
-				}
+		}
+
+		if options.DryRun {
+			if err := automation.planPipeline(pipeline, parameters); err != nil {
+				return err
 			}
+			continue
+		}
+
+		notify.Wrench("Starting to run the pipeline %s", pipeline.Name)
+		if common.IsVerbose() && options.MaxParallel > 1 {
+			notify.Wrench("Running up to %s tasks in parallel for pipeline %s", strconv.Itoa(options.MaxParallel), pipeline.Name)
+		}
+
+		run := newPipelineRun(pipeline, options, previous)
+		run.record.Parameters = parameters
+		if previous != nil {
+			notify.Info("Resuming run %s of pipeline %s as run %s", previous.ID, pipeline.Name, run.record.ID)
+		} else {
+			notify.Info("Pipeline %s run id is %s", pipeline.Name, run.record.ID)
+		}
+
+		err = automation.runPipeline(ctx, run)
+		run.record.finish(err)
+		automation.report.addRun(run.record)
+		if err != nil {
+			notify.Info("Run %s failed, use \"locally lanes resume %s\" to run it again from the failed task", run.record.ID, run.record.ID)
+			return err
 		}
 
 		if !notify.HasErrors() {
@@ -155,7 +268,272 @@ func (automation *PipelineService) Run(name string) error {
 	return nil
 }
 
-func (automation *PipelineService) Validate(name string) error {
+func (automation *PipelineService) runPipeline(ctx context.Context, run *pipelineRun) error {
+	if err := resetOutputs(); err != nil {
+		notify.FromError(err, "There was an error registering the steps vault for pipeline %s", run.pipeline.Name)
+		return err
+	}
+
+	if err := automation.runJobs(ctx, run, &run.status, run.pipeline.Jobs); err != nil {
+		return err
+	}
+
+	result := run.status.firstError()
+	if len(run.pipeline.OnFailure) == 0 && len(run.pipeline.Finally) == 0 {
+		return result
+	}
+
+	if err := publishPipelineStatus(ctx, &run.status); err != nil {
+		notify.FromError(err, "There was an error registering the pipeline vault for pipeline %s", run.pipeline.Name)
+		return err
+	}
+
+	// the cleanup jobs still run after a cancellation so they can stop what the pipeline
+	// started, a second interrupt or the shutdown timeout ends them
+	cleanupCtx := context.WithoutCancel(ctx)
+	run.cleanup = true
+
+	cleanupStatus := runStatus{}
+	if run.status.failed() && len(run.pipeline.OnFailure) > 0 {
+		notify.Info("Running the onFailure jobs for pipeline %s", run.pipeline.Name)
+		if err := automation.runJobs(cleanupCtx, run, &cleanupStatus, run.pipeline.OnFailure); err != nil {
+			cleanupStatus.fail(err)
+		}
+	}
+
+	finallyStatus := runStatus{}
+	if len(run.pipeline.Finally) > 0 {
+		notify.Info("Running the finally jobs for pipeline %s", run.pipeline.Name)
+		if err := automation.runJobs(cleanupCtx, run, &finallyStatus, run.pipeline.Finally); err != nil {
+			finallyStatus.fail(err)
+		}
+	}
+
+	if result == nil {
+		result = cleanupStatus.firstError()
+	}
+	if result == nil {
+		result = finallyStatus.firstError()
+	}
+
+	return result
+}
+
+// runJobs runs a list of jobs in the order of their dependencies, failures are kept in the
+// status instead of stopping the graph so jobs with a failure() or always() condition still get
+// a chance to run
+func (automation *PipelineService) runJobs(ctx context.Context, run *pipelineRun, status *runStatus, jobs []*pipeline_component.PipelineJob) error {
+	graph, err := buildExecutionGraph(jobs)
+	if err != nil {
+		notify.FromError(err, "There was an error building the jobs graph for pipeline %s", run.pipeline.Name)
+		return err
+	}
+
+	graph.Run(run.options.MaxParallel, func(job *pipeline_component.PipelineJob) error {
+		automation.runJob(ctx, run, status, job)
+		return nil
+	})
+
+	return nil
+}
+
+func (automation *PipelineService) runJob(ctx context.Context, run *pipelineRun, status *runStatus, job *pipeline_component.PipelineJob) {
+	if job.Disabled {
+		notify.Info("Job %s for pipeline %s is disabled, continuing", job.Name, run.pipeline.Name)
+		return
+	}
+
+	if ctx.Err() != nil {
+		status.failTask(job.Name, "", stoppedError(ctx, "pipeline", run.pipeline.Name))
+		return
+	}
+
+	shouldRunJob, err := shouldRun(job.If, status)
+	if err != nil {
+		notify.FromError(err, "There was an error evaluating the condition of job %s in pipeline %s", job.Name, run.pipeline.Name)
+		status.failTask(job.Name, "", err)
+		return
+	}
+	if !shouldRunJob {
+		notify.Info("Job %s for pipeline %s was skipped", job.Name, run.pipeline.Name)
+		return
+	}
+
+	if common.IsVerbose() {
+		notify.Wrench("Starting to execute job %s for pipeline %s", job.Name, run.pipeline.Name)
+	}
+
+	steps, err := buildExecutionGraph(job.Steps)
+	if err != nil {
+		notify.FromError(err, "There was an error building the steps graph for job %s in pipeline %s", job.Name, run.pipeline.Name)
+		status.failTask(job.Name, "", err)
+		return
+	}
+
+	jobCtx, cancel, err := withTimeout(ctx, job.Timeout)
+	if err != nil {
+		notify.FromError(err, "There was an error setting the timeout of job %s in pipeline %s", job.Name, run.pipeline.Name)
+		status.failTask(job.Name, "", err)
+		return
+	}
+	defer cancel()
+
+	jobStatus := runStatus{}
+	steps.Run(run.options.MaxParallel, func(step *pipeline_component.PipelineTask) error {
+		automation.runStep(jobCtx, run, job, &jobStatus, step)
+		return nil
+	})
+
+	if jobCtx.Err() != nil && ctx.Err() == nil {
+		err := stoppedError(jobCtx, "job", job.Name)
+		notify.Error("Job %s for pipeline %s timed out after %s", job.Name, run.pipeline.Name, job.Timeout)
+		jobStatus.fail(err)
+	}
+
+	if _, step, err := jobStatus.failure(); err != nil {
+		status.failTask(job.Name, step, err)
+	}
+}
+
+func (automation *PipelineService) runStep(ctx context.Context, run *pipelineRun, job *pipeline_component.PipelineJob, status *runStatus, step *pipeline_component.PipelineTask) {
+	record := PipelineTaskRecord{
+		Job:       job.Name,
+		Step:      step.Name,
+		State:     entities.StateIgnored.String(),
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		record.FinishedAt = time.Now()
+		run.record.addTask(&record)
+	}()
+
+	if step.Disabled {
+		notify.Info("Step %s in job %s for pipeline %s is disabled, continuing", step.Name, job.Name, run.pipeline.Name)
+		return
+	}
+
+	if previous := run.succeededBefore(job, step); previous != nil {
+		notify.Info("Step %s in job %s for pipeline %s already succeeded in run %s, skipping", step.Name, job.Name, run.pipeline.Name, run.previous.ID)
+		restoreOutputs(step, previous.Output, previous.Outputs)
+		publishStatus(step, stepStatusSuccess)
+		record.State = previous.State
+		record.Resumed = true
+		record.Output = previous.Output
+		record.Outputs = previous.Outputs
+		return
+	}
+
+	if ctx.Err() != nil {
+		err := stoppedError(ctx, "task", step.Name)
+		notify.Info("Step %s in job %s for pipeline %s was not started, %s", step.Name, job.Name, run.pipeline.Name, err.Error())
+		publishStatus(step, stepStatusFailure)
+		status.failTask(job.Name, step.Name, err)
+		record.State = entities.StateErrored.String()
+		record.ErrorCode = ErrorCancelled
+		record.Error = err.Error()
+		return
+	}
+
+	shouldRunStep, err := shouldRun(step.If, status)
+	if err != nil {
+		notify.FromError(err, "There was an error evaluating the condition of task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+		status.failTask(job.Name, step.Name, err)
+		record.State = entities.StateErrored.String()
+		record.Error = err.Error()
+		return
+	}
+	if !shouldRunStep {
+		notify.Info("Step %s in job %s for pipeline %s was skipped", step.Name, job.Name, run.pipeline.Name)
+		publishStatus(step, stepStatusSkipped)
+		return
+	}
+
+	run.acquire(step)
+	defer run.release(step)
+
+	if common.IsVerbose() {
+		notify.Wrench("Starting to execute task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+	}
+	policy, err := retry.NewPolicy(step)
+	if err != nil {
+		notify.FromError(err, "There was an error reading the retry policy of task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+		publishStatus(step, stepStatusFailure)
+		status.failTask(job.Name, step.Name, err)
+		record.State = entities.StateErrored.String()
+		record.Error = err.Error()
+		return
+	}
+
+	stepCtx, cancel, err := withTimeout(ctx, step.Timeout)
+	if err != nil {
+		notify.FromError(err, "There was an error setting the timeout of task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+		publishStatus(step, stepStatusFailure)
+		status.failTask(job.Name, step.Name, err)
+		record.State = entities.StateErrored.String()
+		record.Error = err.Error()
+		return
+	}
+	defer cancel()
+
+	record.StartedAt = time.Now()
+	result := retry.Run(stepCtx, policy, step.Name, func(ctx context.Context) entities.PipelineWorkerResult {
+		result, _ := automation.execute(ctx, step)
+		return result
+	})
+	err = result.Error
+	record.State = result.State.String()
+	record.Output = result.Output
+	if err != nil && stepCtx.Err() != nil {
+		err = stoppedError(stepCtx, "task", step.Name)
+		result.ErrorCode = ErrorCancelled
+	}
+	if err != nil {
+		notify.FromError(err, "There was an error executing the task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+		publishStatus(step, stepStatusFailure)
+		status.failTask(job.Name, step.Name, err)
+		record.State = entities.StateErrored.String()
+		record.ErrorCode = result.ErrorCode
+		record.Error = err.Error()
+		return
+	}
+
+	if result.State == entities.StateExecuted {
+		outputs, err := publishOutputs(step, result)
+		record.Outputs = outputs
+		if err != nil {
+			notify.FromError(err, "There was an error publishing the outputs of task %s in job %s for pipeline %s", step.Name, job.Name, run.pipeline.Name)
+			publishStatus(step, stepStatusFailure)
+			status.failTask(job.Name, step.Name, err)
+			record.State = entities.StateErrored.String()
+			record.Error = err.Error()
+			return
+		}
+	}
+
+	publishStatus(step, stepStatusSuccess)
+}
+
+// preparePipeline expands the templates used by the pipeline and resolves its parameters
+func (automation *PipelineService) preparePipeline(pipeline *pipeline_component.Pipeline, values map[string]string, required bool) (*pipeline_component.Pipeline, map[string]string, error) {
+	config := configuration.Get()
+	context := config.GetCurrentContext()
+
+	parameters, err := resolveParameters(pipeline.Parameters, values, required)
+	if err != nil {
+		return pipeline, parameters, err
+	}
+
+	expanded, err := expandPipeline(pipeline, context.PipelineTemplates, parameters)
+	if err != nil {
+		return pipeline, parameters, err
+	}
+
+	return expanded, parameters, nil
+}
+
+// Validate checks the pipeline definition and the inputs of every task, when validating before
+// a run the options are given and all required parameters need a value
+func (automation *PipelineService) Validate(name string, options *PipelineRunOptions) error {
 	pipelines := automation.GetPipelines(name, true)
 
 	if len(pipelines) == 0 {
@@ -164,22 +542,162 @@ func (automation *PipelineService) Validate(name string) error {
 		return err
 	}
 
+	var values map[string]string
+	if options != nil {
+		values = options.Parameters
+		if options.ResumeFrom != "" && len(values) == 0 {
+			if record, err := automation.GetRun(options.ResumeFrom); err == nil {
+				values = record.Parameters
+			}
+		}
+	}
+
 	for _, pipeline := range pipelines {
 		notify.Wrench("Starting to validate the pipeline %s", pipeline.Name)
-		for _, job := range pipeline.Jobs {
+		suite := newPipelineReportSuite(pipeline.Name, ReportKindValidate)
+		err := automation.validatePipeline(pipeline, values, options == nil, suite)
+		if err != nil && !suite.hasErrors() {
+			suite.addTask(pipeline.Name, "", entities.StateErrored, "", err, time.Since(suite.StartedAt), "")
+		}
+		suite.finish(err)
+		automation.report.add(suite)
+		if err != nil {
+			return err
+		}
+
+		if !notify.HasErrors() {
+			notify.Success("Finished validating the pipeline %s", pipeline.Name)
+		}
+	}
+
+	return nil
+}
+
+// validatePipeline checks a single pipeline and adds the validated tasks to the report suite
+func (automation *PipelineService) validatePipeline(pipeline *pipeline_component.Pipeline, values map[string]string, validateOnly bool, suite *PipelineReportSuite) error {
+	pipeline, _, err := automation.preparePipeline(pipeline, values, !validateOnly)
+	if err != nil {
+		err = fmt.Errorf("there was an error validating %s, %s", pipeline.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	if validateOnly {
+		for _, parameter := range pipeline.Parameters {
+			if _, ok := getParameterValue(values, parameter.Name); !ok && parameter.IsRequired() {
+				notify.Warning("Parameter %s of pipeline %s is required, use --param %s=<value> when running it", parameter.Name, pipeline.Name, parameter.Name)
+			}
+		}
+	}
+	if err := validateCondition(pipeline.If); err != nil {
+		err = fmt.Errorf("there was an error validating the condition of %s, %s", pipeline.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	if err := validateStepNames(pipeline); err != nil {
+		err = fmt.Errorf("there was an error validating %s, %s", pipeline.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	for _, jobs := range [][]*pipeline_component.PipelineJob{pipeline.Jobs, pipeline.OnFailure, pipeline.Finally} {
+		if err := automation.validateJobs(pipeline, jobs, suite); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateStepNames checks that the jobs are declared once and that the step names are unique
+// across the jobs of the pipeline as the outputs of the steps are published by their name
+func validateStepNames(pipeline *pipeline_component.Pipeline) error {
+	jobNames := make(map[string]bool)
+	stepJobs := make(map[string]string)
+	for _, jobs := range [][]*pipeline_component.PipelineJob{pipeline.Jobs, pipeline.OnFailure, pipeline.Finally} {
+		for _, job := range jobs {
+			if jobNames[strings.ToLower(job.Name)] {
+				return fmt.Errorf("job %s is declared more than once", job.Name)
+			}
+			jobNames[strings.ToLower(job.Name)] = true
+
 			for _, step := range job.Steps {
-				if !automation.validate(step) {
-					err := fmt.Errorf("there was an error validating the task %s.%s.%s", pipeline.Name, job.Name, step.Name)
-					notify.Error(err.Error())
-					return err
+				if other, ok := stepJobs[strings.ToLower(step.Name)]; ok && !strings.EqualFold(other, job.Name) {
+					return fmt.Errorf("step %s is declared in jobs %s and %s, step names need to be unique in the pipeline as the outputs are published by the step name", step.Name, other, job.Name)
 				}
+				stepJobs[strings.ToLower(step.Name)] = job.Name
 			}
 		}
+	}
 
-		if !notify.HasErrors() {
-			notify.Success("Finished validating the pipeline %s", pipeline.Name)
+	return nil
+}
+
+// validateJobs checks the dependencies, settings and tasks of a list of jobs of a pipeline
+func (automation *PipelineService) validateJobs(pipeline *pipeline_component.Pipeline, jobs []*pipeline_component.PipelineJob, suite *PipelineReportSuite) error {
+	if _, err := buildExecutionGraph(jobs); err != nil {
+		err = fmt.Errorf("there was an error validating the jobs dependencies of %s, %s", pipeline.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+
+	for _, job := range jobs {
+		if _, err := parseTimeout(job.Timeout); err != nil {
+			err = fmt.Errorf("there was an error validating the timeout of %s.%s, %s", pipeline.Name, job.Name, err.Error())
+			notify.Error(err.Error())
+			suite.addTask(job.Name, "", entities.StateErrored, "", err, 0, "")
+			return err
+		}
+		if err := validateCondition(job.If); err != nil {
+			err = fmt.Errorf("there was an error validating the condition of %s.%s, %s", pipeline.Name, job.Name, err.Error())
+			notify.Error(err.Error())
+			suite.addTask(job.Name, "", entities.StateErrored, "", err, 0, "")
+			return err
+		}
+		if _, err := buildExecutionGraph(job.Steps); err != nil {
+			err = fmt.Errorf("there was an error validating the steps dependencies of %s.%s, %s", pipeline.Name, job.Name, err.Error())
+			notify.Error(err.Error())
+			suite.addTask(job.Name, "", entities.StateErrored, "", err, 0, "")
+			return err
+		}
+
+		for _, step := range job.Steps {
+			startedAt := time.Now()
+			err := automation.validateStep(pipeline, job, step)
+			state := entities.StateValid
+			if err != nil {
+				state = entities.StateErrored
+			}
+			suite.addTask(job.Name, step.Name, state, "", err, time.Since(startedAt), "")
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
+
+// validateStep checks the settings of a step and asks the workers to validate its inputs
+func (automation *PipelineService) validateStep(pipeline *pipeline_component.Pipeline, job *pipeline_component.PipelineJob, step *pipeline_component.PipelineTask) error {
+	if _, err := parseTimeout(step.Timeout); err != nil {
+		err = fmt.Errorf("there was an error validating the timeout of %s.%s.%s, %s", pipeline.Name, job.Name, step.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	if _, err := retry.NewPolicy(step); err != nil {
+		err = fmt.Errorf("there was an error validating the retry policy of %s.%s.%s, %s", pipeline.Name, job.Name, step.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	if err := validateCondition(step.If); err != nil {
+		err = fmt.Errorf("there was an error validating the condition of %s.%s.%s, %s", pipeline.Name, job.Name, step.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+	if err := automation.validate(step); err != nil {
+		err = fmt.Errorf("there was an error validating the task %s.%s.%s, %s", pipeline.Name, job.Name, step.Name, err.Error())
+		notify.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
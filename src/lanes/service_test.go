@@ -0,0 +1,60 @@
+package lanes
+
+import (
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+)
+
+func TestPipelineService_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    *pipeline_component.PipelineTask
+		wantErr bool
+	}{
+		{"task without worker", &pipeline_component.PipelineTask{Name: "register", Type: pipeline_component.EmsTask}, true},
+		{"unknown task type", &pipeline_component.PipelineTask{Name: "unknown", Type: pipeline_component.UnknownTask}, true},
+		{"invalid proxy command", &pipeline_component.PipelineTask{Name: "proxy", Type: pipeline_component.ProxyTask, Inputs: map[string]interface{}{"command": "restart"}}, true},
+		{"proxy", &pipeline_component.PipelineTask{Name: "proxy", Type: pipeline_component.ProxyTask, Inputs: map[string]interface{}{"command": "up", "build": true}}, false},
+		{"manifest without path", &pipeline_component.PipelineTask{Name: "manifest", Type: pipeline_component.WebClientManifestTask}, true},
+		{"manifest", &pipeline_component.PipelineTask{Name: "manifest", Type: pipeline_component.WebClientManifestTask, Inputs: map[string]interface{}{"path": "env.js", "format": "js"}}, false},
+	}
+
+	service := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateStepNames(t *testing.T) {
+	job := func(name string, steps ...string) *pipeline_component.PipelineJob {
+		result := &pipeline_component.PipelineJob{Name: name}
+		for _, step := range steps {
+			result.Steps = append(result.Steps, &pipeline_component.PipelineTask{Name: step})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		pipeline *pipeline_component.Pipeline
+		wantErr  bool
+	}{
+		{"unique steps", &pipeline_component.Pipeline{Jobs: []*pipeline_component.PipelineJob{job("api", "clone", "build"), job("web", "clone-web")}}, false},
+		{"duplicate job", &pipeline_component.Pipeline{Jobs: []*pipeline_component.PipelineJob{job("api"), job("API")}}, true},
+		{"same step in two jobs", &pipeline_component.Pipeline{Jobs: []*pipeline_component.PipelineJob{job("api", "clone"), job("web", "Clone")}}, true},
+		{"same step in a cleanup job", &pipeline_component.Pipeline{Jobs: []*pipeline_component.PipelineJob{job("api", "docker")}, Finally: []*pipeline_component.PipelineJob{job("cleanup", "docker")}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStepNames(tt.pipeline); (err != nil) != tt.wantErr {
+				t.Errorf("validateStepNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
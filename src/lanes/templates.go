@@ -0,0 +1,286 @@
+package lanes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+
+	"gopkg.in/yaml.v3"
+)
+
+var parameterConditionRegex = regexp.MustCompile(`\bparameters\.([A-Za-z0-9_\-]+)`)
+
+// templateExpander replaces the jobs and steps that use a template with the steps of the template
+type templateExpander struct {
+	templates  []*pipeline_component.PipelineTemplate
+	parameters map[string]string
+}
+
+// expandPipeline returns a copy of the pipeline where the template jobs and steps are replaced by
+// the steps of their templates, the pipeline from the configuration is not changed and the
+// resolved pipeline parameters are applied to the values given to the templates
+func expandPipeline(pipeline *pipeline_component.Pipeline, templates []*pipeline_component.PipelineTemplate, parameters map[string]string) (*pipeline_component.Pipeline, error) {
+	var result pipeline_component.Pipeline
+	if err := deepCopy(pipeline, &result); err != nil {
+		return nil, err
+	}
+	result.Source = pipeline.Source
+
+	expander := templateExpander{
+		templates:  templates,
+		parameters: parameters,
+	}
+
+	for _, jobs := range [][]*pipeline_component.PipelineJob{result.Jobs, result.OnFailure, result.Finally} {
+		if err := expander.expandJobs(jobs); err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
+
+// expandJobs replaces the template of the jobs, or the template steps they have, with steps
+func (expander *templateExpander) expandJobs(jobs []*pipeline_component.PipelineJob) error {
+	for _, job := range jobs {
+		if job.Template != "" {
+			if len(job.Steps) > 0 {
+				return fmt.Errorf("job %s cannot have steps and a template", job.Name)
+			}
+
+			steps, err := expander.expandTemplate(job.Template, job.With, []string{})
+			if err != nil {
+				return fmt.Errorf("job %s, %s", job.Name, err.Error())
+			}
+			job.Steps = steps
+			continue
+		}
+
+		steps, err := expander.expandSteps(job.Steps, []string{})
+		if err != nil {
+			return fmt.Errorf("job %s, %s", job.Name, err.Error())
+		}
+		job.Steps = steps
+	}
+
+	return nil
+}
+
+// expandSteps replaces the template steps by the steps of the template, the new steps are named
+// <step>-<template step> and steps that depended on the template step depend on all of them
+func (expander *templateExpander) expandSteps(steps []*pipeline_component.PipelineTask, stack []string) ([]*pipeline_component.PipelineTask, error) {
+	result := make([]*pipeline_component.PipelineTask, 0)
+	replaced := make(map[string][]string)
+
+	for _, step := range steps {
+		if step.Template == "" {
+			result = append(result, step)
+			continue
+		}
+
+		expanded, err := expander.expandTemplate(step.Template, step.With, stack)
+		if err != nil {
+			return nil, fmt.Errorf("step %s, %s", step.Name, err.Error())
+		}
+
+		names := make(map[string]string)
+		for _, inner := range expanded {
+			names[strings.ToLower(inner.Name)] = fmt.Sprintf("%s-%s", step.Name, inner.Name)
+		}
+
+		required := make(map[string]bool)
+		for _, inner := range expanded {
+			for _, dependency := range inner.DependsOn {
+				required[strings.ToLower(dependency)] = true
+			}
+		}
+
+		for _, inner := range expanded {
+			// steps that nothing else in the template depends on are the ones that finish it
+			if !required[strings.ToLower(inner.Name)] {
+				replaced[strings.ToLower(step.Name)] = append(replaced[strings.ToLower(step.Name)], names[strings.ToLower(inner.Name)])
+			}
+
+			inner.Name = names[strings.ToLower(inner.Name)]
+			if len(inner.DependsOn) == 0 {
+				inner.DependsOn = append([]string{}, step.DependsOn...)
+			} else {
+				for i, dependency := range inner.DependsOn {
+					if name, ok := names[strings.ToLower(dependency)]; ok {
+						inner.DependsOn[i] = name
+					}
+				}
+			}
+
+			if step.Disabled {
+				inner.Disabled = true
+			}
+			if step.If != "" {
+				if inner.If == "" {
+					inner.If = step.If
+				} else {
+					inner.If = fmt.Sprintf("(%s) && (%s)", step.If, inner.If)
+				}
+			}
+			if inner.Timeout == "" {
+				inner.Timeout = step.Timeout
+			}
+			if inner.Retry == nil {
+				inner.Retry = step.Retry
+			}
+		}
+
+		result = append(result, expanded...)
+	}
+
+	for _, step := range result {
+		dependencies := make([]string, 0)
+		for _, dependency := range step.DependsOn {
+			if names, ok := replaced[strings.ToLower(dependency)]; ok {
+				dependencies = append(dependencies, names...)
+			} else {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		step.DependsOn = dependencies
+	}
+
+	return result, nil
+}
+
+// expandTemplate returns a copy of the template steps with the parameter values applied
+func (expander *templateExpander) expandTemplate(name string, with map[string]interface{}, stack []string) ([]*pipeline_component.PipelineTask, error) {
+	for _, parent := range stack {
+		if strings.EqualFold(parent, name) {
+			return nil, fmt.Errorf("template %s uses itself, %s", name, strings.Join(append(stack, name), " -> "))
+		}
+	}
+
+	var template *pipeline_component.PipelineTemplate
+	for _, candidate := range expander.templates {
+		if strings.EqualFold(candidate.Name, name) {
+			template = candidate
+			break
+		}
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template %s was not found", name)
+	}
+
+	// the pipeline parameters are replaced first so typed template parameters can take them, the
+	// ones without a value, like when validating without parameters, are kept as they are
+	declared := make(map[string]bool)
+	for _, definition := range template.Parameters {
+		declared[strings.ToLower(definition.Name)] = true
+	}
+
+	values := make(map[string]string)
+	pending := make(map[string]string)
+	for key, value := range with {
+		replaced := replaceParameters(fmt.Sprintf("%v", value), expander.parameters)
+		if declared[strings.ToLower(key)] && parameterReferenceRegex.MatchString(replaced) {
+			pending[strings.ToLower(key)] = replaced
+			continue
+		}
+		values[key] = replaced
+	}
+
+	definitions := make([]*pipeline_component.PipelineParameter, 0)
+	for _, definition := range template.Parameters {
+		if _, ok := pending[strings.ToLower(definition.Name)]; !ok {
+			definitions = append(definitions, definition)
+		}
+	}
+
+	parameters, err := resolveParameters(definitions, values, true)
+	if err != nil {
+		return nil, fmt.Errorf("template %s, %s", template.Name, err.Error())
+	}
+	for key, value := range pending {
+		parameters[key] = value
+	}
+
+	steps := make([]*pipeline_component.PipelineTask, 0)
+	if err := deepCopy(template.Steps, &steps); err != nil {
+		return nil, err
+	}
+
+	for _, step := range steps {
+		applyTemplateParameters(step, parameters)
+	}
+
+	return expander.expandSteps(steps, append(stack, template.Name))
+}
+
+// applyTemplateParameters replaces the template parameters in a step, references to parameters
+// that the template does not declare are kept so they resolve to the pipeline parameters
+func applyTemplateParameters(step *pipeline_component.PipelineTask, parameters map[string]string) {
+	step.If = replaceConditionParameters(step.If, parameters)
+	step.WorkingDirectory = replaceParameters(step.WorkingDirectory, parameters)
+	step.Body = replaceParameters(step.Body, parameters)
+	step.Timeout = replaceParameters(step.Timeout, parameters)
+	for key, value := range step.Outputs {
+		step.Outputs[key] = replaceParameters(value, parameters)
+	}
+	for key, value := range step.Inputs {
+		step.Inputs[key] = replaceInputParameters(value, parameters)
+	}
+	for key, value := range step.With {
+		step.With[key] = replaceInputParameters(value, parameters)
+	}
+}
+
+func replaceInputParameters(value interface{}, parameters map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replaceParameters(v, parameters)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = replaceInputParameters(item, parameters)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = replaceInputParameters(item, parameters)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// replaceConditionParameters replaces the parameters in a condition with string literals
+func replaceConditionParameters(condition string, parameters map[string]string) string {
+	if condition == "" {
+		return condition
+	}
+
+	quoted := make(map[string]string)
+	for key, value := range parameters {
+		if strings.Contains(value, "'") {
+			quoted[key] = fmt.Sprintf("\"%s\"", value)
+		} else {
+			quoted[key] = fmt.Sprintf("'%s'", value)
+		}
+	}
+
+	condition = replaceParameters(condition, quoted)
+	return parameterConditionRegex.ReplaceAllStringFunc(condition, func(reference string) string {
+		name := parameterConditionRegex.FindStringSubmatch(reference)[1]
+		if value, ok := quoted[strings.ToLower(name)]; ok {
+			return value
+		}
+		return reference
+	})
+}
+
+func deepCopy(source interface{}, target interface{}) error {
+	content, err := yaml.Marshal(source)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(content, target)
+}
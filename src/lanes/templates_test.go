@@ -0,0 +1,209 @@
+package lanes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+)
+
+func TestResolveParameters(t *testing.T) {
+	definitions := []*pipeline_component.PipelineParameter{
+		{Name: "tenant"},
+		{Name: "migrate", Type: pipeline_component.ParameterTypeBool, Default: true},
+		{Name: "replicas", Type: pipeline_component.ParameterTypeInt, Default: 1},
+		{Name: "environment", Type: pipeline_component.ParameterTypeChoice, Values: []string{"dev", "test"}, Default: "dev"},
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		required bool
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			"defaults",
+			map[string]string{"tenant": "acme"},
+			true,
+			map[string]string{"tenant": "acme", "migrate": "true", "replicas": "1", "environment": "dev"},
+			false,
+		},
+		{
+			"typed values",
+			map[string]string{"Tenant": "acme", "migrate": "FALSE", "replicas": "3", "environment": "TEST"},
+			true,
+			map[string]string{"tenant": "acme", "migrate": "false", "replicas": "3", "environment": "test"},
+			false,
+		},
+		{"missing required", map[string]string{}, true, nil, true},
+		{"missing required when validating", map[string]string{}, false, map[string]string{"migrate": "true", "replicas": "1", "environment": "dev"}, false},
+		{"invalid bool", map[string]string{"tenant": "acme", "migrate": "maybe"}, true, nil, true},
+		{"invalid int", map[string]string{"tenant": "acme", "replicas": "many"}, true, nil, true},
+		{"invalid choice", map[string]string{"tenant": "acme", "environment": "prod"}, true, nil, true},
+		{"unknown parameter", map[string]string{"tenant": "acme", "region": "eu"}, true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveParameters(definitions, tt.values, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPipeline(t *testing.T) {
+	templates := []*pipeline_component.PipelineTemplate{
+		{
+			Name: "clone-build",
+			Parameters: []*pipeline_component.PipelineParameter{
+				{Name: "repoUrl"},
+				{Name: "migrate", Type: pipeline_component.ParameterTypeBool, Default: false},
+			},
+			Steps: []*pipeline_component.PipelineTask{
+				{Name: "clone", Type: pipeline_component.GitTask, Inputs: map[string]interface{}{"repoUrl": "${{ parameters.repoUrl }}"}},
+				{Name: "build", Type: pipeline_component.DotnetTask, DependsOn: []string{"clone"}, Inputs: map[string]interface{}{"tenant": "${{ parameters.tenant }}"}},
+				{Name: "migrate", Type: pipeline_component.EFMigrationTask, DependsOn: []string{"build"}, If: "parameters.migrate == 'true'"},
+			},
+		},
+	}
+
+	pipeline := &pipeline_component.Pipeline{
+		Name: "seed",
+		Jobs: []*pipeline_component.PipelineJob{
+			{Name: "api", Template: "clone-build", With: map[string]interface{}{"repoUrl": "https://example.com/api.git", "migrate": true}},
+			{
+				Name: "web",
+				Steps: []*pipeline_component.PipelineTask{
+					{Name: "prepare", Type: pipeline_component.BashTask},
+					{Name: "app", Template: "clone-build", DependsOn: []string{"prepare"}, With: map[string]interface{}{"repoUrl": "https://example.com/web.git"}},
+					{Name: "publish", Type: pipeline_component.BashTask, DependsOn: []string{"app"}},
+				},
+			},
+		},
+		Finally: []*pipeline_component.PipelineJob{
+			{Name: "cleanup", Template: "clone-build", With: map[string]interface{}{"repoUrl": "https://example.com/cleanup.git"}},
+		},
+	}
+
+	expanded, err := expandPipeline(pipeline, templates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := expanded.Jobs[0]
+	if len(api.Steps) != 3 || api.Steps[0].Inputs["repoUrl"] != "https://example.com/api.git" {
+		t.Errorf("job template was not expanded, got %v steps", len(api.Steps))
+	}
+	if api.Steps[1].Inputs["tenant"] != "${{ parameters.tenant }}" {
+		t.Errorf("undeclared parameters should be kept, got %v", api.Steps[1].Inputs["tenant"])
+	}
+	if api.Steps[2].If != "'true' == 'true'" {
+		t.Errorf("condition parameters were not replaced, got %v", api.Steps[2].If)
+	}
+
+	web := expanded.Jobs[1]
+	names := make([]string, 0)
+	for _, step := range web.Steps {
+		names = append(names, step.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"prepare", "app-clone", "app-build", "app-migrate", "publish"}) {
+		t.Errorf("unexpected expanded steps %v", names)
+	}
+	if !reflect.DeepEqual(web.Steps[1].DependsOn, []string{"prepare"}) {
+		t.Errorf("first template step should inherit the dependencies, got %v", web.Steps[1].DependsOn)
+	}
+	if !reflect.DeepEqual(web.Steps[2].DependsOn, []string{"app-clone"}) {
+		t.Errorf("template dependencies were not renamed, got %v", web.Steps[2].DependsOn)
+	}
+	if !reflect.DeepEqual(web.Steps[4].DependsOn, []string{"app-migrate"}) {
+		t.Errorf("dependencies on the template step were not replaced, got %v", web.Steps[4].DependsOn)
+	}
+
+	if len(expanded.Finally[0].Steps) != 3 {
+		t.Errorf("finally job template was not expanded, got %v steps", len(expanded.Finally[0].Steps))
+	}
+
+	if pipeline.Jobs[0].Steps != nil || pipeline.Jobs[1].Steps[1].Name != "app" {
+		t.Errorf("the original pipeline was changed")
+	}
+
+	if _, err := buildExecutionGraph(web.Steps); err != nil {
+		t.Errorf("expanded steps do not build a graph, %v", err)
+	}
+}
+
+func TestExpandPipeline_PipelineParameters(t *testing.T) {
+	templates := []*pipeline_component.PipelineTemplate{
+		{
+			Name: "migrate",
+			Parameters: []*pipeline_component.PipelineParameter{
+				{Name: "migrate", Type: pipeline_component.ParameterTypeBool},
+				{Name: "retries", Type: pipeline_component.ParameterTypeInt},
+				{Name: "mode", Type: pipeline_component.ParameterTypeChoice, Values: []string{"up", "down"}},
+			},
+			Steps: []*pipeline_component.PipelineTask{
+				{Name: "run", Type: pipeline_component.EFMigrationTask, If: "parameters.migrate == 'true'", Inputs: map[string]interface{}{"retries": "${{ parameters.retries }}", "mode": "${{ parameters.mode }}"}},
+			},
+		},
+	}
+
+	pipeline := &pipeline_component.Pipeline{
+		Name: "seed",
+		Jobs: []*pipeline_component.PipelineJob{
+			{Name: "api", Template: "migrate", With: map[string]interface{}{"migrate": "${{ parameters.migrate }}", "retries": "${{ parameters.retries }}", "mode": "${{ parameters.mode }}"}},
+		},
+	}
+
+	expanded, err := expandPipeline(pipeline, templates, map[string]string{"migrate": "true", "retries": "3", "mode": "down"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := expanded.Jobs[0].Steps[0]
+	if step.If != "'true' == 'true'" || step.Inputs["retries"] != "3" || step.Inputs["mode"] != "down" {
+		t.Errorf("pipeline parameters were not passed to the template, got %v %v", step.If, step.Inputs)
+	}
+
+	if _, err := expandPipeline(pipeline, templates, map[string]string{"migrate": "maybe", "retries": "3", "mode": "down"}); err == nil {
+		t.Errorf("expected an error for an invalid bool parameter")
+	}
+
+	expanded, err = expandPipeline(pipeline, templates, nil)
+	if err != nil {
+		t.Fatalf("parameters without a value should be kept when validating, %v", err)
+	}
+	if expanded.Jobs[0].Steps[0].Inputs["retries"] != "${{ parameters.retries }}" {
+		t.Errorf("unexpected inputs %v", expanded.Jobs[0].Steps[0].Inputs)
+	}
+}
+
+func TestExpandPipeline_Errors(t *testing.T) {
+	templates := []*pipeline_component.PipelineTemplate{
+		{Name: "loop", Steps: []*pipeline_component.PipelineTask{{Name: "again", Template: "loop"}}},
+		{Name: "needs-url", Parameters: []*pipeline_component.PipelineParameter{{Name: "repoUrl"}}, Steps: []*pipeline_component.PipelineTask{{Name: "clone", Type: pipeline_component.GitTask}}},
+	}
+
+	tests := []struct {
+		name string
+		job  *pipeline_component.PipelineJob
+	}{
+		{"missing template", &pipeline_component.PipelineJob{Name: "job", Template: "unknown"}},
+		{"recursive template", &pipeline_component.PipelineJob{Name: "job", Template: "loop"}},
+		{"missing parameter", &pipeline_component.PipelineJob{Name: "job", Template: "needs-url"}},
+		{"template and steps", &pipeline_component.PipelineJob{Name: "job", Template: "needs-url", With: map[string]interface{}{"repoUrl": "a"}, Steps: []*pipeline_component.PipelineTask{{Name: "other"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &pipeline_component.Pipeline{Name: "test", Jobs: []*pipeline_component.PipelineJob{tt.job}}
+			if _, err := expandPipeline(pipeline, templates, nil); err == nil {
+				t.Errorf("expandPipeline() expected an error")
+			}
+		})
+	}
+}
@@ -1,27 +1,61 @@
 package bashworker
 
 import (
-	"github.com/cjlapao/locally-cli/environment"
+	"errors"
+	"fmt"
 	"strings"
+
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+const (
+	ShellBash       = "bash"
+	ShellSh         = "sh"
+	ShellPowershell = "pwsh"
 )
 
 type BashParameters struct {
-	Command          string   `json:"command,omitempty" yaml:"command,omitempty"`
-	Arguments        []string `json:"arguments,omitempty" yaml:"arguments,omitempty"`
-	WorkingDirectory string   `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
-	RetryCount       int      `json:"retryCount,omitempty" yaml:"retryCount,omitempty"`
-	WaitForInSeconds int      `json:"waitFor,omitempty" yaml:"waitFor,omitempty"`
+	Command          string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Arguments        []string          `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+	WorkingDirectory string            `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
+	Script           string            `json:"script,omitempty" yaml:"script,omitempty"`
+	Shell            string            `json:"shell,omitempty" yaml:"shell,omitempty"`
+	Env              map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	ContinueOnError  bool              `json:"continueOnError,omitempty" yaml:"continueOnError,omitempty"`
+	SuccessExitCodes []int             `json:"successExitCodes,omitempty" yaml:"successExitCodes,omitempty"`
 }
 
 func (c *BashParameters) Validate() bool {
-	return c.Command != ""
+	return c.ValidateOptions() == nil
+}
+
+// ValidateOptions returns why the parameters are not valid
+func (c *BashParameters) ValidateOptions() error {
+	if c.Command == "" && c.Script == "" {
+		return errors.New("a command or a script is required")
+	}
+	if c.Command != "" && c.Script != "" {
+		return errors.New("only one of command or script can be set")
+	}
+	switch strings.ToLower(c.Shell) {
+	case "", ShellBash, ShellSh, ShellPowershell, "powershell":
+	default:
+		return fmt.Errorf("invalid shell %s, it needs to be one of bash, sh or pwsh", c.Shell)
+	}
+	if c.Command != "" {
+		if _, err := splitCommandLine(c.Command); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (c *BashParameters) Decode() {
 	env := environment.Get()
 
-	parts := strings.Split(c.Command, " ")
-	if len(parts) > 1 {
+	// the command is split as a shell would, so quoted arguments can have spaces
+	if parts, err := splitCommandLine(c.Command); err == nil && len(parts) > 0 {
 		c.Command = parts[0]
 		reorderArgs := parts[1:]
 		reorderArgs = append(reorderArgs, c.Arguments...)
@@ -33,4 +67,82 @@ func (c *BashParameters) Decode() {
 	for key, value := range c.Arguments {
 		c.Arguments[key] = env.Replace(value)
 	}
+
+	c.Script = env.ReplaceText(c.Script)
+	c.Shell = strings.ToLower(env.Replace(c.Shell))
+	if c.Shell == "" {
+		c.Shell = ShellBash
+	}
+	if c.Shell == "powershell" {
+		c.Shell = ShellPowershell
+	}
+	for key, value := range c.Env {
+		c.Env[key] = env.Replace(value)
+	}
+}
+
+// IsSuccessExitCode returns true if a failed exit code should be treated as a success
+func (c *BashParameters) IsSuccessExitCode(exitCode int) bool {
+	for _, code := range c.SuccessExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitCommandLine splits a command line in its arguments like a shell, arguments can be quoted
+// with single or double quotes and outside single quotes a backslash escapes a quote or a space,
+// other backslashes are kept so windows paths still work
+func splitCommandLine(commandLine string) ([]string, error) {
+	result := make([]string, 0)
+	var current strings.Builder
+	inArgument := false
+	var quote rune
+	escaped := false
+
+	for _, char := range commandLine {
+		switch {
+		case escaped:
+			if !strings.ContainsRune("\"' \t", char) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(char)
+			escaped = false
+		case char == '\\' && quote != '\'':
+			escaped = true
+			inArgument = true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '\'' || char == '"':
+			quote = char
+			inArgument = true
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			if inArgument {
+				result = append(result, current.String())
+				current.Reset()
+				inArgument = false
+			}
+		default:
+			current.WriteRune(char)
+			inArgument = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command %s", quote, commandLine)
+	}
+	if escaped {
+		current.WriteRune('\\')
+	}
+	if inArgument {
+		result = append(result, current.String())
+	}
+
+	return result, nil
 }
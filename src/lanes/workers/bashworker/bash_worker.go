@@ -1,17 +1,19 @@
 package bashworker
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
 	"github.com/cjlapao/locally-cli/executer"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
-	"github.com/cjlapao/locally-cli/lanes/retry"
 	"github.com/cjlapao/locally-cli/notifications"
 
 	"gopkg.in/yaml.v3"
@@ -38,7 +40,7 @@ func (worker BashPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker BashPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker BashPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.BashTask {
@@ -61,9 +63,15 @@ func (worker BashPipelineWorker) Run(task *pipeline_component.PipelineTask) enti
 
 	inputs.Decode()
 
-	result = retry.RetryRun(task, worker.runTask, inputs.RetryCount, inputs.WaitForInSeconds)
+	result = worker.runTask(ctx, task, inputs)
+	if result.Error != nil {
+		return result
+	}
 
 	msg := fmt.Sprintf("Command executed successfully for task %s", task.Name)
+	if inputs.Script != "" {
+		msg = fmt.Sprintf("Script executed successfully with %s for task %s", inputs.Shell, task.Name)
+	}
 
 	if inputs.WorkingDirectory != "" {
 		msg += fmt.Sprintf(" in folder %s", inputs.WorkingDirectory)
@@ -80,69 +88,175 @@ func (worker BashPipelineWorker) Run(task *pipeline_component.PipelineTask) enti
 	return result
 }
 
-func (worker BashPipelineWorker) runTask(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker BashPipelineWorker) runTask(ctx context.Context, task *pipeline_component.PipelineTask, inputs *BashParameters) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
-	inputs, err := worker.parseParameters(task)
-	if err != nil {
-		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
-	}
+	command := inputs.Command
+	arguments := inputs.Arguments
+	if inputs.Script != "" {
+		scriptCommand, scriptArguments, cleanup, err := prepareScript(inputs.Shell, inputs.Script)
+		if err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		}
+		defer cleanup()
 
-	inputs.Decode()
+		command = scriptCommand
+		arguments = scriptArguments
+	}
 
-	var changeDirErr error
-	currentFolder := ""
+	variables := make([]string, 0)
+	for _, key := range sortedKeys(inputs.Env) {
+		variables = append(variables, fmt.Sprintf("%s=%s", key, inputs.Env[key]))
+	}
 
-	if inputs.WorkingDirectory != "" {
-		currentFolder, changeDirErr = os.Getwd()
-		if changeDirErr != nil {
-			return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, changeDirErr)
-		}
-		changeDirErr = os.Chdir(inputs.WorkingDirectory)
-		if changeDirErr != nil {
-			return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, changeDirErr)
+	notify.Debug("Run arguments: %s", strings.Join(arguments, ","))
+	output, err := executer.ExecuteAndWatchWithEnvContext(ctx, inputs.WorkingDirectory, variables, command, arguments...)
+	result.Output = output.StdOut
+	result.AddOutput("exitCode", strconv.Itoa(output.ExitCode))
+	if err != nil {
+		switch {
+		case ctx.Err() != nil:
+			// a cancelled or timed out step always fails
+		case output.ExitCode > 0 && inputs.IsSuccessExitCode(output.ExitCode):
+			notify.Debug("[%s] exit code %s is a success exit code for task %s", worker.name, strconv.Itoa(output.ExitCode), task.Name)
+			return result
+		case inputs.ContinueOnError:
+			notify.Warning("Command failed for task %s with exit code %s, continuing as continueOnError is set, %s", task.Name, strconv.Itoa(output.ExitCode), err.Error())
+			return result
 		}
+
+		errorResult := entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		errorResult.ExitCode = strconv.Itoa(output.ExitCode)
+		errorResult.Output = result.Output
+		errorResult.Outputs = result.Outputs
+		return errorResult
+	}
+
+	return result
+}
+
+func (worker BashPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.BashTask {
+		result.State = entities.StateIgnored
+		return result
 	}
 
-	notify.Debug("Run arguments: %s", strings.Join(inputs.Arguments, ","))
-	output, err := executer.ExecuteAndWatch(inputs.Command, inputs.Arguments...)
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
-	if inputs.WorkingDirectory != "" {
-		changeDirErr = os.Chdir(currentFolder)
-		if changeDirErr != nil {
-			return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, changeDirErr)
-		}
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("failed validation, %s", err.Error()))
 	}
 
-	result.Output = output.GetAllOutput()
+	result.State = entities.StateValid
 	return result
 }
 
-func (worker BashPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker BashPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 	if task.Type != pipeline_component.BashTask {
 		result.State = entities.StateIgnored
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	lines := make([]string, 0)
+	if inputs.Script != "" {
+		lines = append(lines, fmt.Sprintf("run script with %s", inputs.Shell))
+		for _, line := range strings.Split(strings.TrimRight(inputs.Script, "\n"), "\n") {
+			lines = append(lines, fmt.Sprintf("  %s", line))
+		}
+	} else {
+		commandLine := []string{inputs.Command}
+		for _, argument := range inputs.Arguments {
+			if strings.ContainsAny(argument, " \t\"'") {
+				argument = strconv.Quote(argument)
+			}
+			commandLine = append(commandLine, argument)
+		}
+		lines = append(lines, fmt.Sprintf("run %s", strings.Join(commandLine, " ")))
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	if inputs.WorkingDirectory != "" {
+		lines = append(lines, fmt.Sprintf("in folder %s", inputs.WorkingDirectory))
+	}
+	for _, key := range sortedKeys(inputs.Env) {
+		value := inputs.Env[key]
+		if environment.IsSecretKey(key) {
+			value = environment.MASK
+		}
+		lines = append(lines, fmt.Sprintf("with %s=%s", key, value))
+	}
+	if len(inputs.SuccessExitCodes) > 0 {
+		codes := make([]string, 0)
+		for _, code := range inputs.SuccessExitCodes {
+			codes = append(codes, strconv.Itoa(code))
+		}
+		lines = append(lines, fmt.Sprintf("exit codes %s are a success", strings.Join(codes, ", ")))
+	}
+	if inputs.ContinueOnError {
+		lines = append(lines, "continue if it fails")
 	}
 
+	result.Output = strings.Join(lines, "\n")
 	result.State = entities.StateValid
 	return result
 }
 
+// prepareScript writes the script to a temporary file and returns the command that runs it with
+// the shell, bash and sh stop at the first failing command like a step would
+func prepareScript(shell string, script string) (string, []string, func(), error) {
+	extension := ".sh"
+	if shell == ShellPowershell {
+		extension = ".ps1"
+	}
+
+	file, err := os.CreateTemp("", "locally-script-*"+extension)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cleanup := func() {
+		os.Remove(file.Name())
+	}
+
+	_, err = file.WriteString(script)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, nil, err
+	}
+
+	switch shell {
+	case ShellSh:
+		return "sh", []string{"-e", file.Name()}, cleanup, nil
+	case ShellPowershell:
+		return "pwsh", []string{"-NoProfile", "-NonInteractive", "-File", file.Name()}, cleanup, nil
+	default:
+		return "bash", []string{"--noprofile", "--norc", "-eo", "pipefail", file.Name()}, cleanup, nil
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
 func (worker BashPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*BashParameters, error) {
 	encoded, err := yaml.Marshal(task.Inputs)
 	if err != nil {
@@ -154,5 +268,10 @@ func (worker BashPipelineWorker) parseParameters(task *pipeline_component.Pipeli
 		return nil, err
 	}
 
+	// the body of the task is used as the script when there is no command
+	if inputs.Command == "" && inputs.Script == "" {
+		inputs.Script = task.Body
+	}
+
 	return &inputs, nil
 }
@@ -0,0 +1,102 @@
+package bashworker
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"dotnet build", []string{"dotnet", "build"}, false},
+		{`git commit -m "initial commit"`, []string{"git", "commit", "-m", "initial commit"}, false},
+		{`echo 'it is "quoted"'  done`, []string{"echo", `it is "quoted"`, "done"}, false},
+		{`echo a\ b \"c\"`, []string{"echo", "a b", `"c"`}, false},
+		{`C:\tools\locally.exe --help`, []string{`C:\tools\locally.exe`, "--help"}, false},
+		{`echo ""`, []string{"echo", ""}, false},
+		{`echo "unterminated`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := splitCommandLine(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCommandLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommandLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBashPipelineWorker_Script(t *testing.T) {
+	worker := BashPipelineWorker{}.New()
+	run := func(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+		task.Name = "script"
+		task.Type = pipeline_component.BashTask
+		return worker.Run(context.Background(), task)
+	}
+
+	result := run(&pipeline_component.PipelineTask{Inputs: map[string]interface{}{
+		"script": "echo \"hello $TENANT\"\necho done",
+		"env":    map[string]interface{}{"TENANT": "acme corp"},
+	}})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the script to run, %v", result.Error)
+	}
+	if strings.TrimSpace(result.Output) != "hello acme corp\ndone" {
+		t.Errorf("unexpected output %q", result.Output)
+	}
+
+	result = run(&pipeline_component.PipelineTask{Body: "echo from body", Inputs: map[string]interface{}{"shell": "sh"}})
+	if result.State != entities.StateExecuted || strings.TrimSpace(result.Output) != "from body" {
+		t.Errorf("expected the body to be used as the script, got %q %v", result.Output, result.Error)
+	}
+
+	result = run(&pipeline_component.PipelineTask{Inputs: map[string]interface{}{"script": "false\necho not reached"}})
+	if result.State != entities.StateErrored || result.ExitCode != "1" || strings.Contains(result.Output, "not reached") {
+		t.Errorf("expected the script to stop at the first error, got %v %q", result.State, result.Output)
+	}
+
+	result = run(&pipeline_component.PipelineTask{Inputs: map[string]interface{}{"script": "exit 3", "successExitCodes": []interface{}{3}}})
+	if result.State != entities.StateExecuted || result.Outputs["exitCode"] != "3" {
+		t.Errorf("expected exit code 3 to be a success, got %v", result.State)
+	}
+
+	result = run(&pipeline_component.PipelineTask{Inputs: map[string]interface{}{"command": `sh -c "exit 2"`, "continueOnError": true}})
+	if result.State != entities.StateExecuted || result.Outputs["exitCode"] != "2" {
+		t.Errorf("expected the step to continue on error, got %v %v", result.State, result.Error)
+	}
+}
+
+func TestBashPipelineWorker_Validate(t *testing.T) {
+	worker := BashPipelineWorker{}.New()
+	tests := []struct {
+		name   string
+		inputs map[string]interface{}
+		want   entities.PipelineWorkerResultState
+	}{
+		{"command", map[string]interface{}{"command": "ls -la"}, entities.StateValid},
+		{"nothing to run", map[string]interface{}{}, entities.StateErrored},
+		{"command and script", map[string]interface{}{"command": "ls", "script": "ls"}, entities.StateErrored},
+		{"invalid shell", map[string]interface{}{"script": "ls", "shell": "fish"}, entities.StateErrored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := worker.Validate(&pipeline_component.PipelineTask{Name: "bash", Type: pipeline_component.BashTask, Inputs: tt.inputs})
+			if result.State != tt.want {
+				t.Errorf("Validate() = %v, want %v", result.State, tt.want)
+			}
+		})
+	}
+}
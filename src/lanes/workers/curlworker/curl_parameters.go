@@ -1,16 +1,25 @@
 package curlworker
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/environment"
 )
 
 type CurlParameters struct {
-	Host             string            `json:"host,omitempty" yaml:"host,omitempty"`
-	Verb             string            `json:"verb,omitempty" yaml:"verb,omitempty"`
-	Headers          map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
-	Content          *CurlContent      `json:"content,omitempty" yaml:"content,omitempty"`
-	RetryCount       int               `json:"retryCount,omitempty" yaml:"retryCount,omitempty"`
-	WaitForInSeconds int               `json:"waitFor,omitempty" yaml:"waitFor,omitempty"`
+	Host       string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Verb       string            `json:"verb,omitempty" yaml:"verb,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content    *CurlContent      `json:"content,omitempty" yaml:"content,omitempty"`
+	Auth       *CurlAuth         `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Tls        *CurlTls          `json:"tls,omitempty" yaml:"tls,omitempty"`
+	Expect     *CurlExpect       `json:"expect,omitempty" yaml:"expect,omitempty"`
+	Extract    map[string]string `json:"extract,omitempty" yaml:"extract,omitempty"`
+	OutputFile string            `json:"outputFile,omitempty" yaml:"outputFile,omitempty"`
 }
 
 func (c *CurlParameters) Validate() bool {
@@ -24,31 +33,139 @@ func (c *CurlParameters) Validate() bool {
 	return true
 }
 
+// ValidateOptions checks the optional blocks of the parameters
+func (c *CurlParameters) ValidateOptions() error {
+	if c.Content != nil && c.Content.Multipart != nil && (c.Content.Json != "" || c.Content.UrlEncoded != nil) {
+		return errors.New("content can only have one of json, urlEncoded or multipart")
+	}
+	if c.Auth != nil {
+		if c.Auth.Token != "" && (c.Auth.Username != "" || c.Auth.Password != "") {
+			return errors.New("auth can only have a token or a username and password")
+		}
+		if c.Auth.Token == "" && c.Auth.Username == "" {
+			return errors.New("auth needs a token or a username")
+		}
+	}
+	if c.Expect != nil {
+		status := make([]string, 0)
+		for _, value := range c.Expect.Status {
+			// variables are only known after decoding, the run checks them again
+			if !strings.Contains(value, "${{") {
+				status = append(status, value)
+			}
+		}
+		if _, err := common.ParseStatusCodes(status); err != nil {
+			return err
+		}
+		for header, expression := range c.Expect.Headers {
+			if _, err := regexp.Compile(expression); err != nil {
+				return fmt.Errorf("invalid expression for header %s, %s", header, err.Error())
+			}
+		}
+		if c.Expect.Body != "" {
+			if _, err := regexp.Compile(c.Expect.Body); err != nil {
+				return fmt.Errorf("invalid body expression %s, %s", c.Expect.Body, err.Error())
+			}
+		}
+	}
+	for name, path := range c.Extract {
+		if path == "" {
+			return fmt.Errorf("extract %s needs a json path", name)
+		}
+	}
+
+	return nil
+}
+
 type CurlContent struct {
 	ContentType string            `json:"contentType,omitempty" yaml:"contentType,omitempty"`
 	UrlEncoded  map[string]string `json:"urlEncoded,omitempty" yaml:"urlEncoded,omitempty"`
 	Json        string            `json:"json,omitempty" yaml:"json,omitempty"`
+	Multipart   *CurlMultipart    `json:"multipart,omitempty" yaml:"multipart,omitempty"`
+}
+
+// CurlMultipart is a multipart/form-data body, files maps the form field to the path of the file
+// that is uploaded
+type CurlMultipart struct {
+	Fields map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Files  map[string]string `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// CurlAuth adds an authorization header, a token is sent as a bearer token and a username and
+// password as basic authentication
+type CurlAuth struct {
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Token    string `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+type CurlTls struct {
+	CaCert           string `json:"caCert,omitempty" yaml:"caCert,omitempty"`
+	RootCertificates bool   `json:"rootCertificates,omitempty" yaml:"rootCertificates,omitempty"`
+	Insecure         bool   `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+}
+
+// CurlExpect are the assertions on the response, headers and body are regular expressions and the
+// json values are compared with the value in the json path
+type CurlExpect struct {
+	Status  []string          `json:"status,omitempty" yaml:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Json    map[string]string `json:"json,omitempty" yaml:"json,omitempty"`
+	Body    string            `json:"body,omitempty" yaml:"body,omitempty"`
 }
 
 func (c *CurlParameters) Decode() {
 	env := environment.Get()
 
 	c.Host = env.Replace(c.Host)
+	c.Verb = env.Replace(c.Verb)
+	c.OutputFile = env.Replace(c.OutputFile)
 	for key, value := range c.Headers {
 		c.Headers[key] = env.Replace(value)
 	}
 
 	if c.Content != nil {
-		if c.Content.ContentType != "" {
-			c.Content.ContentType = env.Replace(c.Content.ContentType)
+		c.Content.ContentType = env.Replace(c.Content.ContentType)
+		c.Content.Json = env.Replace(c.Content.Json)
+
+		for key, value := range c.Content.UrlEncoded {
+			c.Content.UrlEncoded[key] = env.Replace(value)
 		}
 
-		if c.Content.ContentType != "" {
-			c.Content.Json = env.Replace(c.Content.Json)
+		if c.Content.Multipart != nil {
+			for key, value := range c.Content.Multipart.Fields {
+				c.Content.Multipart.Fields[key] = env.Replace(value)
+			}
+			for key, value := range c.Content.Multipart.Files {
+				c.Content.Multipart.Files[key] = env.Replace(value)
+			}
 		}
+	}
 
-		for key, value := range c.Content.UrlEncoded {
-			c.Content.UrlEncoded[key] = env.Replace(value)
+	if c.Auth != nil {
+		c.Auth.Username = env.Replace(c.Auth.Username)
+		c.Auth.Password = env.Replace(c.Auth.Password)
+		c.Auth.Token = env.Replace(c.Auth.Token)
+	}
+
+	if c.Tls != nil {
+		c.Tls.CaCert = env.Replace(c.Tls.CaCert)
+	}
+
+	if c.Expect != nil {
+		for i, status := range c.Expect.Status {
+			c.Expect.Status[i] = env.Replace(status)
 		}
+		for key, value := range c.Expect.Headers {
+			c.Expect.Headers[key] = env.Replace(value)
+		}
+		for key, value := range c.Expect.Json {
+			c.Expect.Json[key] = env.Replace(value)
+		}
+		c.Expect.Body = env.Replace(c.Expect.Body)
+	}
+
+	for key, value := range c.Extract {
+		c.Extract[key] = env.Replace(value)
 	}
 }
@@ -1,22 +1,33 @@
 package curlworker
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 
 	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/environment"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
-	"github.com/cjlapao/locally-cli/lanes/retry"
 	"github.com/cjlapao/locally-cli/notifications"
 
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/cjlapao/common-go/helper"
 	_ "github.com/microsoft/go-mssqldb"
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +37,7 @@ var notify = notifications.Get()
 const (
 	ErrorInvalidParameters = "500"
 	ErrorInvalidConnection = "501"
+	ErrorExpectationFailed = "417"
 )
 
 type CurlPipelineWorker struct {
@@ -42,7 +54,7 @@ func (worker CurlPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker CurlPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker CurlPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.CurlTask {
@@ -64,8 +76,12 @@ func (worker CurlPipelineWorker) Run(task *pipeline_component.PipelineTask) enti
 	}
 
 	inputs.Decode()
+	// the expectations are checked again as a variable can resolve to an invalid expression
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
 
-	result = retry.RetryRun(task, worker.runTask, inputs.RetryCount, inputs.WaitForInSeconds)
+	result = worker.runTask(ctx, inputs)
 
 	if result.Error != nil {
 		return result
@@ -90,83 +106,135 @@ func (worker CurlPipelineWorker) Validate(task *pipeline_component.PipelineTask)
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	if !inputs.Validate() {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, errors.New("failed validation, host is required"))
+	}
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
 	result.State = entities.StateValid
 	return result
 }
 
-func (worker CurlPipelineWorker) runTask(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker CurlPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
-	env := environment.Get()
+	if task.Type != pipeline_component.CurlTask {
+		result.State = entities.StateIgnored
+		return result
+	}
 
 	inputs, err := worker.parseParameters(task)
 	if err != nil {
 		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
+	inputs.Validate()
 	inputs.Decode()
 
-	var request *http.Request
-	inputs.Host = env.Replace(inputs.Host)
+	lines := []string{fmt.Sprintf("%s %s", strings.ToUpper(inputs.Verb), inputs.Host)}
+	for key, value := range inputs.Headers {
+		if environment.IsSecretKey(key) {
+			value = environment.MASK
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", key, value))
+	}
+
 	if inputs.Content != nil {
 		if inputs.Content.Json != "" {
-			if inputs.Content.ContentType == "" {
-				inputs.Content.ContentType = "application/json"
+			contentType := inputs.Content.ContentType
+			if contentType == "" {
+				contentType = "application/json"
 			}
-			request, err = http.NewRequest(inputs.Verb, inputs.Host, strings.NewReader(inputs.Content.Json))
-			if err != nil {
-				return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
-			}
-			request.Header.Add("Content-Type", inputs.Content.ContentType)
+			lines = append(lines, fmt.Sprintf("Content-Type: %s", contentType), "", inputs.Content.Json)
 		} else if inputs.Content.UrlEncoded != nil {
-			if inputs.Content.ContentType == "" {
-				inputs.Content.ContentType = "application/x-www-form-urlencoded"
+			contentType := inputs.Content.ContentType
+			if contentType == "" {
+				contentType = "application/x-www-form-urlencoded"
 			}
-
 			data := url.Values{}
 			for key, value := range inputs.Content.UrlEncoded {
+				if environment.IsSecretKey(key) {
+					value = environment.MASK
+				}
 				data.Add(key, value)
 			}
-
-			notify.Debug("Data: %s", data.Encode())
-			request, err = http.NewRequest(inputs.Verb, inputs.Host, strings.NewReader(data.Encode()))
-			if err != nil {
-				return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
+			lines = append(lines, fmt.Sprintf("Content-Type: %s", contentType), "", data.Encode())
+		} else if inputs.Content.Multipart != nil {
+			lines = append(lines, "Content-Type: multipart/form-data", "")
+			for _, key := range sortedKeys(inputs.Content.Multipart.Fields) {
+				value := inputs.Content.Multipart.Fields[key]
+				if environment.IsSecretKey(key) {
+					value = environment.MASK
+				}
+				lines = append(lines, fmt.Sprintf("field %s = %s", key, value))
+			}
+			for _, key := range sortedKeys(inputs.Content.Multipart.Files) {
+				lines = append(lines, fmt.Sprintf("file %s = %s", key, inputs.Content.Multipart.Files[key]))
 			}
-			request.Header.Add("Content-Type", inputs.Content.ContentType)
-		}
-	} else {
-		request, err = http.NewRequest(inputs.Verb, inputs.Host, nil)
-		if err != nil {
-			return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
 		}
 	}
 
-	if request == nil {
-		return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
+	if inputs.Auth != nil {
+		if inputs.Auth.Token != "" {
+			lines = append(lines, "with bearer authentication")
+		} else {
+			lines = append(lines, fmt.Sprintf("with basic authentication as %s", inputs.Auth.Username))
+		}
 	}
-
-	// Adding the extra headers into the project
-	if inputs.Headers != nil {
-		for key, value := range inputs.Headers {
-			request.Header.Add(key, value)
+	if inputs.Tls != nil && inputs.Tls.Insecure {
+		lines = append(lines, "skipping the certificate validation")
+	}
+	if inputs.Expect != nil {
+		if len(inputs.Expect.Status) > 0 {
+			lines = append(lines, fmt.Sprintf("expect status %s", strings.Join(inputs.Expect.Status, ", ")))
+		}
+		for _, key := range sortedKeys(inputs.Expect.Headers) {
+			lines = append(lines, fmt.Sprintf("expect header %s to match %s", key, inputs.Expect.Headers[key]))
+		}
+		for _, key := range sortedKeys(inputs.Expect.Json) {
+			lines = append(lines, fmt.Sprintf("expect %s to be %s", key, inputs.Expect.Json[key]))
+		}
+		if inputs.Expect.Body != "" {
+			lines = append(lines, fmt.Sprintf("expect the body to match %s", inputs.Expect.Body))
 		}
 	}
+	for _, key := range sortedKeys(inputs.Extract) {
+		lines = append(lines, fmt.Sprintf("extract %s from %s", key, inputs.Extract[key]))
+	}
+	if inputs.OutputFile != "" {
+		lines = append(lines, fmt.Sprintf("write the response to %s", inputs.OutputFile))
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker CurlPipelineWorker) runTask(ctx context.Context, inputs *CurlParameters) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	request, err := newRequest(ctx, inputs)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
 
 	for k, v := range request.Header {
+		if environment.IsSecretKey(k) {
+			v = []string{environment.MASK}
+		}
 		notify.Debug("Header %s: %s", k, v)
 	}
-	client := &http.Client{}
+
+	client, err := newClient(inputs.Tls)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
 
 	response, err := client.Do(request)
 	if err != nil {
@@ -178,23 +246,281 @@ func (worker CurlPipelineWorker) runTask(task *pipeline_component.PipelineTask)
 	defer response.Body.Close()
 
 	b, err := io.ReadAll(response.Body)
-	// b, err := ioutil.ReadAll(resp.Body)  Go.1.15 and earlier
 	if err != nil {
 		return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
 	}
 
 	notify.Debug("%s: got %s\n %s", inputs.Host, fmt.Sprintf("%v", response.StatusCode), string(b))
+	result.AddOutput("statusCode", result.StatusCode)
+
+	if inputs.OutputFile != "" {
+		if err := writeOutputFile(inputs.OutputFile, b); err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+		}
+		result.Output = inputs.OutputFile
+		result.AddOutput("path", inputs.OutputFile)
+	} else {
+		result.Output = string(b)
+	}
 
-	if response.StatusCode <= 199 || response.StatusCode >= 400 {
+	if inputs.Expect != nil && len(inputs.Expect.Status) > 0 {
+		accepted, err := common.ParseStatusCodes(inputs.Expect.Status)
+		if err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+		}
+		if !common.MatchStatusCode(response.StatusCode, accepted) {
+			result.State = entities.StateErrored
+			result.Error = fmt.Errorf("unexpected status code %v, expected %s", response.StatusCode, strings.Join(inputs.Expect.Status, ", "))
+			result.ErrorCode = fmt.Sprintf("%v", response.StatusCode)
+			return result
+		}
+	} else if response.StatusCode <= 199 || response.StatusCode >= 400 {
 		result.State = entities.StateErrored
 		result.Error = fmt.Errorf("invalid success status code, %v", response.StatusCode)
 		result.ErrorCode = fmt.Sprintf("%v", response.StatusCode)
 		return result
 	}
 
+	if err := checkExpectations(inputs.Expect, response.Header, string(b)); err != nil {
+		result.State = entities.StateErrored
+		result.Error = err
+		result.ErrorCode = ErrorExpectationFailed
+		return result
+	}
+
+	for _, name := range sortedKeys(inputs.Extract) {
+		value, err := common.GetJsonPathString(string(b), inputs.Extract[name])
+		if err != nil {
+			result.State = entities.StateErrored
+			result.Error = fmt.Errorf("could not extract %s from %s, %s", name, inputs.Extract[name], err.Error())
+			result.ErrorCode = ErrorExpectationFailed
+			return result
+		}
+		result.AddOutput(name, value)
+	}
+
 	return result
 }
 
+// newRequest creates the request with its body, headers and authentication
+func newRequest(ctx context.Context, inputs *CurlParameters) (*http.Request, error) {
+	var body io.Reader
+	contentType := ""
+	if inputs.Content != nil {
+		contentType = inputs.Content.ContentType
+		if inputs.Content.Json != "" {
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			body = strings.NewReader(inputs.Content.Json)
+		} else if inputs.Content.UrlEncoded != nil {
+			if contentType == "" {
+				contentType = "application/x-www-form-urlencoded"
+			}
+
+			data := url.Values{}
+			for key, value := range inputs.Content.UrlEncoded {
+				data.Add(key, value)
+			}
+
+			body = strings.NewReader(data.Encode())
+		} else if inputs.Content.Multipart != nil {
+			content, multipartType, err := newMultipartBody(inputs.Content.Multipart)
+			if err != nil {
+				return nil, err
+			}
+			contentType = multipartType
+			body = content
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, inputs.Verb, inputs.Host, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		request.Header.Add("Content-Type", contentType)
+	}
+
+	// Adding the extra headers into the project
+	for key, value := range inputs.Headers {
+		request.Header.Add(key, value)
+	}
+
+	if inputs.Auth != nil {
+		if inputs.Auth.Token != "" {
+			request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", inputs.Auth.Token))
+		} else {
+			request.SetBasicAuth(inputs.Auth.Username, inputs.Auth.Password)
+		}
+	}
+
+	return request, nil
+}
+
+// newMultipartBody builds a multipart/form-data body with the fields and files, it returns the
+// body and its content type with the boundary
+func newMultipartBody(content *CurlMultipart) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, key := range sortedKeys(content.Fields) {
+		if err := writer.WriteField(key, content.Fields[key]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, key := range sortedKeys(content.Files) {
+		path := content.Files[key]
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not open file %s for field %s, %s", path, key, err.Error())
+		}
+
+		part, err := writer.CreateFormFile(key, filepath.Base(path))
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		file.Close()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// newClient creates the http client with the tls options, the custom certificates are trusted on
+// top of the system ones
+func newClient(options *CurlTls) (*http.Client, error) {
+	client := &http.Client{}
+	if options == nil {
+		return client, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options.Insecure,
+	}
+
+	if options.CaCert != "" || options.RootCertificates {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if options.CaCert != "" {
+			content, err := os.ReadFile(options.CaCert)
+			if err != nil {
+				return nil, fmt.Errorf("could not read the ca certificate %s, %s", options.CaCert, err.Error())
+			}
+			if !pool.AppendCertsFromPEM(content) {
+				return nil, fmt.Errorf("the ca certificate %s does not contain any pem certificate", options.CaCert)
+			}
+		}
+
+		if options.RootCertificates {
+			for _, certificate := range getGeneratedCertificates() {
+				pool.AppendCertsFromPEM([]byte(certificate))
+			}
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+
+	return client, nil
+}
+
+// getGeneratedCertificates returns the root and intermediate certificates generated by locally
+func getGeneratedCertificates() []string {
+	result := make([]string, 0)
+	config := configuration.Get()
+	if config.GlobalConfiguration == nil || config.GlobalConfiguration.CertificateGenerator == nil {
+		return result
+	}
+
+	for _, root := range config.GlobalConfiguration.CertificateGenerator.Root {
+		if root.PemCertificate != "" {
+			result = append(result, root.PemCertificate)
+		}
+		for _, intermediate := range root.IntermediateCertificates {
+			if intermediate.PemCertificate != "" {
+				result = append(result, intermediate.PemCertificate)
+			}
+		}
+	}
+
+	return result
+}
+
+// checkExpectations checks the headers and body of the response against the expect block
+func checkExpectations(expect *CurlExpect, headers http.Header, body string) error {
+	if expect == nil {
+		return nil
+	}
+
+	for _, name := range sortedKeys(expect.Headers) {
+		value := headers.Get(name)
+		expression, err := regexp.Compile(expect.Headers[name])
+		if err != nil {
+			return fmt.Errorf("invalid expression for header %s, %s", name, err.Error())
+		}
+		if !expression.MatchString(value) {
+			return fmt.Errorf("header %s with value %s does not match %s", name, value, expect.Headers[name])
+		}
+	}
+
+	for _, path := range sortedKeys(expect.Json) {
+		value, err := common.GetJsonPathString(body, path)
+		if err != nil {
+			return fmt.Errorf("could not read %s from the response, %s", path, err.Error())
+		}
+		if value != expect.Json[path] {
+			return fmt.Errorf("%s is %s, expected %s", path, value, expect.Json[path])
+		}
+	}
+
+	if expect.Body != "" {
+		expression, err := regexp.Compile(expect.Body)
+		if err != nil {
+			return fmt.Errorf("invalid body expression %s, %s", expect.Body, err.Error())
+		}
+		if !expression.MatchString(body) {
+			return fmt.Errorf("the response body does not match %s", expect.Body)
+		}
+	}
+
+	return nil
+}
+
+func writeOutputFile(path string, content []byte) error {
+	folder := filepath.Dir(path)
+	if !helper.DirectoryExists(folder) {
+		if err := os.MkdirAll(folder, fs.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
 func (worker CurlPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*CurlParameters, error) {
 	encoded, err := yaml.Marshal(task.Inputs)
 	if err != nil {
@@ -0,0 +1,198 @@
+package curlworker
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func runTask(inputs map[string]interface{}) entities.PipelineWorkerResult {
+	worker := CurlPipelineWorker{}.New()
+	return worker.Run(context.Background(), &pipeline_component.PipelineTask{Name: "curl", Type: pipeline_component.CurlTask, Inputs: inputs})
+}
+
+func TestCurlPipelineWorker_ExpectAndExtract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"42","tenant":{"name":"acme"},"items":[{"enabled":true}]}`))
+	}))
+	defer server.Close()
+
+	inputs := func() map[string]interface{} {
+		return map[string]interface{}{
+			"host": server.URL,
+			"verb": "POST",
+			"auth": map[string]interface{}{"token": "abc"},
+			"expect": map[string]interface{}{
+				"status":  []interface{}{"201"},
+				"headers": map[string]interface{}{"Content-Type": "^application/json"},
+				"json":    map[string]interface{}{"$.tenant.name": "acme", "$.items[0].enabled": "true"},
+			},
+			"extract": map[string]interface{}{"tenantId": "$.id"},
+		}
+	}
+
+	result := runTask(inputs())
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the request to succeed, %v", result.Error)
+	}
+	if result.Outputs["tenantId"] != "42" || result.Outputs["statusCode"] != "201" {
+		t.Errorf("unexpected outputs %v", result.Outputs)
+	}
+
+	failing := inputs()
+	failing["expect"].(map[string]interface{})["json"] = map[string]interface{}{"$.tenant.name": "contoso"}
+	result = runTask(failing)
+	if result.State != entities.StateErrored || result.ErrorCode != ErrorExpectationFailed {
+		t.Errorf("expected the json assertion to fail, got %v", result.State)
+	}
+
+	unauthorized := inputs()
+	delete(unauthorized, "auth")
+	unauthorized["expect"] = map[string]interface{}{"status": []interface{}{"4xx"}}
+	unauthorized["extract"] = nil
+	result = runTask(unauthorized)
+	if result.State != entities.StateExecuted || result.Outputs["statusCode"] != "401" {
+		t.Errorf("expected a 4xx status to be accepted, got %v %v", result.State, result.Error)
+	}
+}
+
+func TestCurlPipelineWorker_MultipartAndOutputFile(t *testing.T) {
+	folder := t.TempDir()
+	upload := filepath.Join(folder, "tenants.csv")
+	if err := os.WriteFile(upload, []byte("id,name\n1,acme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		w.Write([]byte(r.FormValue("tenant") + ":" + header.Filename + ":" + string(content)))
+	}))
+	defer server.Close()
+
+	output := filepath.Join(folder, "out", "response.txt")
+	result := runTask(map[string]interface{}{
+		"host": server.URL,
+		"verb": "POST",
+		"auth": map[string]interface{}{"username": "admin", "password": "secret"},
+		"content": map[string]interface{}{
+			"multipart": map[string]interface{}{
+				"fields": map[string]interface{}{"tenant": "acme"},
+				"files":  map[string]interface{}{"file": upload},
+			},
+		},
+		"outputFile": output,
+	})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the upload to succeed, %v", result.Error)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "acme:tenants.csv:id,name\n1,acme\n" || result.Outputs["path"] != output {
+		t.Errorf("unexpected response %q", string(content))
+	}
+}
+
+func TestCurlPipelineWorker_Tls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	if result := runTask(map[string]interface{}{"host": server.URL}); result.State != entities.StateErrored {
+		t.Errorf("expected an untrusted certificate to fail")
+	}
+
+	if result := runTask(map[string]interface{}{"host": server.URL, "tls": map[string]interface{}{"insecure": true}}); result.State != entities.StateExecuted {
+		t.Errorf("expected insecure to skip the validation, %v", result.Error)
+	}
+
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	content := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caCert, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if result := runTask(map[string]interface{}{"host": server.URL, "tls": map[string]interface{}{"caCert": caCert}}); result.State != entities.StateExecuted {
+		t.Errorf("expected the custom ca to be trusted, %v", result.Error)
+	}
+}
+
+func TestCurlPipelineWorker_InvalidDecodedExpectations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	env := environment.Get()
+	env.Add("global", "curl-status", "ok")
+	env.Add("global", "curl-body", "(")
+
+	tests := []struct {
+		name   string
+		expect map[string]interface{}
+	}{
+		{"status", map[string]interface{}{"status": []interface{}{"${{ global.curl-status }}"}}},
+		{"header", map[string]interface{}{"headers": map[string]interface{}{"Content-Type": "${{ global.curl-body }}"}}},
+		{"body", map[string]interface{}{"body": "${{ global.curl-body }}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runTask(map[string]interface{}{"host": server.URL, "expect": tt.expect})
+			if result.State != entities.StateErrored || result.ErrorCode != ErrorInvalidParameters {
+				t.Errorf("expected invalid parameters, got %v %v", result.State, result.Error)
+			}
+		})
+	}
+}
+
+func TestCurlParameters_ValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  CurlParameters
+		wantErr bool
+	}{
+		{"no options", CurlParameters{Host: "http://localhost"}, false},
+		{"token and password", CurlParameters{Auth: &CurlAuth{Token: "a", Password: "b"}}, true},
+		{"empty auth", CurlParameters{Auth: &CurlAuth{}}, true},
+		{"json and multipart", CurlParameters{Content: &CurlContent{Json: "{}", Multipart: &CurlMultipart{}}}, true},
+		{"invalid status", CurlParameters{Expect: &CurlExpect{Status: []string{"ok"}}}, true},
+		{"invalid header expression", CurlParameters{Expect: &CurlExpect{Headers: map[string]string{"Location": "("}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.inputs.ValidateOptions(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
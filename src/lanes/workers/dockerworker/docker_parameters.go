@@ -1,6 +1,7 @@
 package dockerworker
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,13 @@ import (
 	"github.com/cjlapao/locally-cli/environment"
 )
 
+const (
+	CommandBuild = "build"
+	CommandPush  = "push"
+	CommandRun   = "run"
+	CommandExec  = "exec"
+)
+
 type DockerParameters struct {
 	Command              string                          `json:"command,omitempty" yaml:"command,omitempty"`
 	Registry             string                          `json:"registry,omitempty" yaml:"registry,omitempty"`
@@ -24,17 +32,102 @@ type DockerParameters struct {
 	Arguments            map[string]string               `json:"arguments,omitempty" yaml:"arguments,omitempty"`
 	EnvironmentVariables map[string]string               `json:"environmentVars,omitempty" yaml:"environmentVars,omitempty"`
 	DockerCompose        *docker_component.DockerCompose `json:"dockerCompose,omitempty" yaml:"dockerCompose,omitempty"`
+	Build                *DockerBuildParameters          `json:"build,omitempty" yaml:"build,omitempty"`
+	Push                 *DockerPushParameters           `json:"push,omitempty" yaml:"push,omitempty"`
+	Run                  *DockerRunParameters            `json:"run,omitempty" yaml:"run,omitempty"`
+	Exec                 *DockerExecParameters           `json:"exec,omitempty" yaml:"exec,omitempty"`
+}
+
+// DockerBuildParameters builds an image from a dockerfile
+type DockerBuildParameters struct {
+	Context    string            `json:"context,omitempty" yaml:"context,omitempty"`
+	Dockerfile string            `json:"dockerfile,omitempty" yaml:"dockerfile,omitempty"`
+	Target     string            `json:"target,omitempty" yaml:"target,omitempty"`
+	BuildArgs  map[string]string `json:"buildArgs,omitempty" yaml:"buildArgs,omitempty"`
+	Tags       []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	NoCache    bool              `json:"noCache,omitempty" yaml:"noCache,omitempty"`
+	Platform   string            `json:"platform,omitempty" yaml:"platform,omitempty"`
+}
+
+// DockerPushParameters pushes images to their registry
+type DockerPushParameters struct {
+	Images []string `json:"images,omitempty" yaml:"images,omitempty"`
+}
+
+// DockerRunParameters runs a one off container
+type DockerRunParameters struct {
+	Image      string            `json:"image,omitempty" yaml:"image,omitempty"`
+	Name       string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Entrypoint string            `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	Command    []string          `json:"command,omitempty" yaml:"command,omitempty"`
+	Env        map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Mounts     []string          `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	Network    string            `json:"network,omitempty" yaml:"network,omitempty"`
+	WorkingDir string            `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
+	User       string            `json:"user,omitempty" yaml:"user,omitempty"`
+	Keep       bool              `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// DockerExecParameters runs a command in a running container, the container is either given by
+// name or is the running container of the service in configName
+type DockerExecParameters struct {
+	Container  string            `json:"container,omitempty" yaml:"container,omitempty"`
+	Command    []string          `json:"command,omitempty" yaml:"command,omitempty"`
+	Env        map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	WorkingDir string            `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
+	User       string            `json:"user,omitempty" yaml:"user,omitempty"`
 }
 
 func (c *DockerParameters) Validate() bool {
+	return c.ValidateOptions() == nil
+}
+
+// ValidateOptions returns why the parameters are not valid
+func (c *DockerParameters) ValidateOptions() error {
+	switch c.Command {
+	case CommandBuild:
+		if c.Build == nil {
+			return c.validateRegistry()
+		}
+		if len(c.Build.Tags) == 0 {
+			return errors.New("build needs at least one tag")
+		}
+	case CommandPush:
+		if c.Push == nil || len(c.Push.Images) == 0 {
+			return errors.New("push needs at least one image")
+		}
+	case CommandRun:
+		if c.Run == nil || c.Run.Image == "" {
+			return errors.New("run needs an image")
+		}
+		for _, mount := range c.Run.Mounts {
+			if !strings.Contains(mount, ":") {
+				return fmt.Errorf("invalid mount %s, it needs to be source:target", mount)
+			}
+		}
+	case CommandExec:
+		if c.Exec == nil || len(c.Exec.Command) == 0 {
+			return errors.New("exec needs a command")
+		}
+		if c.Exec.Container == "" && c.ConfigName == "" {
+			return errors.New("exec needs a container or the configName of a service")
+		}
+	default:
+		return c.validateRegistry()
+	}
+
+	return nil
+}
+
+func (c *DockerParameters) validateRegistry() error {
 	if c.Registry == "" {
-		return false
+		return errors.New("registry is required")
 	}
 	if c.ImagePath == "" {
-		return false
+		return errors.New("imagePath is required")
 	}
 
-	return true
+	return nil
 }
 
 func (c *DockerParameters) Decode() {
@@ -87,4 +180,61 @@ func (c *DockerParameters) Decode() {
 	for key, value := range c.EnvironmentVariables {
 		c.EnvironmentVariables[key] = env.Replace(value)
 	}
+
+	if c.Build != nil {
+		c.Build.Context = env.Replace(c.Build.Context)
+		c.Build.Dockerfile = env.Replace(c.Build.Dockerfile)
+		c.Build.Target = env.Replace(c.Build.Target)
+		c.Build.Platform = env.Replace(c.Build.Platform)
+		replaceAll(c.Build.Tags)
+		replaceMap(c.Build.BuildArgs)
+	}
+	if c.Push != nil {
+		replaceAll(c.Push.Images)
+	}
+	if c.Run != nil {
+		c.Run.Image = env.Replace(c.Run.Image)
+		c.Run.Name = env.Replace(c.Run.Name)
+		c.Run.Entrypoint = env.Replace(c.Run.Entrypoint)
+		c.Run.Network = env.Replace(c.Run.Network)
+		c.Run.WorkingDir = env.Replace(c.Run.WorkingDir)
+		c.Run.User = env.Replace(c.Run.User)
+		replaceAll(c.Run.Command)
+		replaceAll(c.Run.Mounts)
+		replaceMap(c.Run.Env)
+	}
+	if c.Exec != nil {
+		c.Exec.Container = env.Replace(c.Exec.Container)
+		c.Exec.WorkingDir = env.Replace(c.Exec.WorkingDir)
+		c.Exec.User = env.Replace(c.Exec.User)
+		replaceAll(c.Exec.Command)
+		replaceMap(c.Exec.Env)
+	}
+}
+
+// isContainerCommand returns true for the commands that work with images and containers directly,
+// build only does it when it has a build section as otherwise it builds a locally service
+func (c *DockerParameters) isContainerCommand() bool {
+	switch c.Command {
+	case CommandBuild:
+		return c.Build != nil
+	case CommandPush, CommandRun, CommandExec:
+		return true
+	default:
+		return false
+	}
+}
+
+func replaceAll(values []string) {
+	env := environment.Get()
+	for key, value := range values {
+		values[key] = env.Replace(value)
+	}
+}
+
+func replaceMap(values map[string]string) {
+	env := environment.Get()
+	for key, value := range values {
+		values[key] = env.Replace(value)
+	}
 }
@@ -1,13 +1,19 @@
 package dockerworker
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/context/docker_component"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/docker"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/executer"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
 	"github.com/cjlapao/locally-cli/notifications"
@@ -39,7 +45,7 @@ func (worker DockerPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker DockerPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker DockerPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.DockerTask {
@@ -62,6 +68,22 @@ func (worker DockerPipelineWorker) Run(task *pipeline_component.PipelineTask) en
 
 	inputs.Decode()
 
+	if inputs.isContainerCommand() {
+		result = worker.runContainerCommand(ctx, inputs)
+		if result.Error != nil {
+			return result
+		}
+
+		msg := fmt.Sprintf("Docker %s executed successfully for task %s", inputs.Command, task.Name)
+		if common.IsDebug() {
+			msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+		}
+		notify.Success(msg)
+
+		result.State = entities.StateExecuted
+		return result
+	}
+
 	options := docker.DockerServiceOptions{
 		Name:          inputs.ConfigName,
 		ComponentName: inputs.ComponentName,
@@ -110,21 +132,60 @@ func (worker DockerPipelineWorker) Validate(task *pipeline_component.PipelineTas
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("failed validation, %s", err.Error()))
 	}
 
 	result.State = entities.StateValid
 	return result
 }
 
+func (worker DockerPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.DockerTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	if inputs.isContainerCommand() {
+		result.Output = strings.Join(planContainerCommand(inputs), "\n")
+		result.State = entities.StateValid
+		return result
+	}
+
+	image := fmt.Sprintf("%s/%s", strings.TrimRight(inputs.Registry, "/"), inputs.FullImagePath)
+	if inputs.ImageTag != "" {
+		image = fmt.Sprintf("%s:%s", image, inputs.ImageTag)
+	}
+
+	lines := []string{fmt.Sprintf("run docker %s", inputs.Command), fmt.Sprintf("image %s", image)}
+	if inputs.ConfigName != "" {
+		lines = append(lines, fmt.Sprintf("configuration %s", inputs.ConfigName))
+	}
+	if inputs.ComponentName != "" {
+		lines = append(lines, fmt.Sprintf("component %s", inputs.ComponentName))
+	}
+	if inputs.Username != "" {
+		lines = append(lines, fmt.Sprintf("registry user %s", inputs.Username))
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
 func (worker DockerPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*DockerParameters, error) {
 	encoded, err := yaml.Marshal(task.Inputs)
 	if err != nil {
@@ -138,3 +199,176 @@ func (worker DockerPipelineWorker) parseParameters(task *pipeline_component.Pipe
 
 	return &inputs, nil
 }
+
+// runContainerCommand builds, pushes or runs images with docker directly instead of using the
+// locally docker commands
+func (worker DockerPipelineWorker) runContainerCommand(ctx context.Context, inputs *DockerParameters) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	wrapper := docker.GetWrapper()
+
+	switch inputs.Command {
+	case CommandBuild:
+		idFile, err := os.CreateTemp("", "locally-image-*.id")
+		if err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecutingWrapper, err)
+		}
+		idFile.Close()
+		defer os.Remove(idFile.Name())
+
+		output, err := wrapper.BuildImageContext(ctx, docker.ImageBuildOptions{
+			Context:     inputs.Build.Context,
+			Dockerfile:  inputs.Build.Dockerfile,
+			Target:      inputs.Build.Target,
+			BuildArgs:   inputs.Build.BuildArgs,
+			Tags:        inputs.Build.Tags,
+			NoCache:     inputs.Build.NoCache,
+			Platform:    inputs.Build.Platform,
+			ImageIdFile: idFile.Name(),
+		})
+		if err != nil {
+			return newErrorResult(output, err)
+		}
+
+		result.AddOutput("image", inputs.Build.Tags[0])
+		result.AddOutput("tags", strings.Join(inputs.Build.Tags, ","))
+		if content, err := os.ReadFile(idFile.Name()); err == nil {
+			result.Output = strings.TrimSpace(string(content))
+			result.AddOutput("imageId", result.Output)
+		}
+	case CommandPush:
+		if inputs.Username != "" {
+			if err := wrapper.Login(inputs.Registry, inputs.Username, inputs.Password, inputs.SubscriptionId, inputs.TenantId); err != nil {
+				return entities.NewPipelineWorkerResultFromError(ErrorInvalidLogin, err)
+			}
+		}
+
+		for _, image := range inputs.Push.Images {
+			output, err := wrapper.PushImageContext(ctx, image)
+			if err != nil {
+				return newErrorResult(output, err)
+			}
+		}
+
+		result.Output = strings.Join(inputs.Push.Images, "\n")
+		result.AddOutput("images", strings.Join(inputs.Push.Images, ","))
+	case CommandRun:
+		output, err := wrapper.RunContainerContext(ctx, docker.ContainerRunOptions{
+			Image:      inputs.Run.Image,
+			Name:       inputs.Run.Name,
+			Entrypoint: inputs.Run.Entrypoint,
+			Command:    inputs.Run.Command,
+			Env:        inputs.Run.Env,
+			Mounts:     inputs.Run.Mounts,
+			Network:    inputs.Run.Network,
+			WorkingDir: inputs.Run.WorkingDir,
+			User:       inputs.Run.User,
+			Keep:       inputs.Run.Keep,
+		})
+		if err != nil {
+			return newErrorResult(output, err)
+		}
+
+		result.Output = output.GetAllOutput()
+		result.AddOutput("exitCode", strconv.Itoa(output.ExitCode))
+	case CommandExec:
+		container := inputs.Exec.Container
+		if container == "" {
+			id, err := docker.Get().GetServiceContainerId(ctx, &docker.DockerServiceOptions{
+				Name:          inputs.ConfigName,
+				ComponentName: inputs.ComponentName,
+			})
+			if err != nil {
+				return entities.NewPipelineWorkerResultFromError(ErrorExecutingWrapper, err)
+			}
+			container = id
+		}
+
+		output, err := wrapper.ExecContainerContext(ctx, docker.ContainerExecOptions{
+			Container:  container,
+			Command:    inputs.Exec.Command,
+			Env:        inputs.Exec.Env,
+			WorkingDir: inputs.Exec.WorkingDir,
+			User:       inputs.Exec.User,
+		})
+		if err != nil {
+			return newErrorResult(output, err)
+		}
+
+		result.Output = output.GetAllOutput()
+		result.AddOutput("exitCode", strconv.Itoa(output.ExitCode))
+	}
+
+	return result
+}
+
+// newErrorResult keeps the output and exit code of a failed docker command in the result
+func newErrorResult(output executer.ExecuteOutput, err error) entities.PipelineWorkerResult {
+	result := entities.NewPipelineWorkerResultFromError(ErrorExecutingWrapper, err)
+	result.Output = output.GetAllOutput()
+	result.ExitCode = strconv.Itoa(output.ExitCode)
+	result.AddOutput("exitCode", result.ExitCode)
+
+	return result
+}
+
+func planContainerCommand(inputs *DockerParameters) []string {
+	lines := make([]string, 0)
+	withEnv := func(variables map[string]string) {
+		keys := make([]string, 0)
+		for key := range variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := variables[key]
+			if environment.IsSecretKey(key) {
+				value = environment.MASK
+			}
+			lines = append(lines, fmt.Sprintf("with %s=%s", key, value))
+		}
+	}
+
+	switch inputs.Command {
+	case CommandBuild:
+		context := inputs.Build.Context
+		if context == "" {
+			context = "."
+		}
+		lines = append(lines, fmt.Sprintf("build image %s from %s", strings.Join(inputs.Build.Tags, ", "), context))
+		if inputs.Build.Dockerfile != "" {
+			lines = append(lines, fmt.Sprintf("dockerfile %s", inputs.Build.Dockerfile))
+		}
+		if inputs.Build.Target != "" {
+			lines = append(lines, fmt.Sprintf("target %s", inputs.Build.Target))
+		}
+		withEnv(inputs.Build.BuildArgs)
+	case CommandPush:
+		for _, image := range inputs.Push.Images {
+			lines = append(lines, fmt.Sprintf("push image %s", image))
+		}
+		if inputs.Username != "" {
+			lines = append(lines, fmt.Sprintf("registry user %s", inputs.Username))
+		}
+	case CommandRun:
+		lines = append(lines, fmt.Sprintf("run container from %s %s", inputs.Run.Image, strings.Join(inputs.Run.Command, " ")))
+		if inputs.Run.Network != "" {
+			lines = append(lines, fmt.Sprintf("in network %s", inputs.Run.Network))
+		}
+		for _, mount := range inputs.Run.Mounts {
+			lines = append(lines, fmt.Sprintf("mount %s", mount))
+		}
+		withEnv(inputs.Run.Env)
+	case CommandExec:
+		container := inputs.Exec.Container
+		if container == "" {
+			container = fmt.Sprintf("of service %s", inputs.ConfigName)
+			if inputs.ComponentName != "" {
+				container = fmt.Sprintf("%s component %s", container, inputs.ComponentName)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("run %s in container %s", strings.Join(inputs.Exec.Command, " "), container))
+		withEnv(inputs.Exec.Env)
+	}
+
+	return lines
+}
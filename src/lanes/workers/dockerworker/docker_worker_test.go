@@ -0,0 +1,58 @@
+package dockerworker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestDockerPipelineWorker_Validate(t *testing.T) {
+	worker := DockerPipelineWorker{}.New()
+	tests := []struct {
+		name   string
+		inputs map[string]interface{}
+		want   entities.PipelineWorkerResultState
+	}{
+		{"pull", map[string]interface{}{"command": "pull", "registry": "example.azurecr.io", "imagePath": "api"}, entities.StateValid},
+		{"pull without registry", map[string]interface{}{"command": "pull"}, entities.StateErrored},
+		{"build image", map[string]interface{}{"command": "build", "build": map[string]interface{}{"tags": []interface{}{"api:latest"}}}, entities.StateValid},
+		{"build image without tags", map[string]interface{}{"command": "build", "build": map[string]interface{}{"context": "."}}, entities.StateErrored},
+		{"push", map[string]interface{}{"command": "push", "push": map[string]interface{}{"images": []interface{}{"api:latest"}}}, entities.StateValid},
+		{"run without image", map[string]interface{}{"command": "run", "run": map[string]interface{}{}}, entities.StateErrored},
+		{"exec in service", map[string]interface{}{"command": "exec", "configName": "api", "exec": map[string]interface{}{"command": []interface{}{"ls"}}}, entities.StateValid},
+		{"exec without container", map[string]interface{}{"command": "exec", "exec": map[string]interface{}{"command": []interface{}{"ls"}}}, entities.StateErrored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := worker.Validate(&pipeline_component.PipelineTask{Name: "docker", Type: pipeline_component.DockerTask, Inputs: tt.inputs})
+			if result.State != tt.want {
+				t.Errorf("Validate() = %v, want %v, %v", result.State, tt.want, result.Error)
+			}
+		})
+	}
+}
+
+func TestDockerPipelineWorker_PlanRun(t *testing.T) {
+	worker := DockerPipelineWorker{}.New().(DockerPipelineWorker)
+	result := worker.Plan(&pipeline_component.PipelineTask{Name: "seed", Type: pipeline_component.DockerTask, Inputs: map[string]interface{}{
+		"command": "run",
+		"run": map[string]interface{}{
+			"image":   "postgres:16",
+			"command": []interface{}{"psql", "-f", "/seed/seed.sql"},
+			"network": "locally",
+			"env":     map[string]interface{}{"PGPASSWORD": "secret"},
+		},
+	}})
+	if result.State != entities.StateValid {
+		t.Fatalf("expected a plan, %v", result.Error)
+	}
+
+	want := "run container from postgres:16 psql -f /seed/seed.sql\nin network locally\nwith PGPASSWORD=" + environment.MASK
+	if strings.TrimSpace(result.Output) != want {
+		t.Errorf("unexpected plan %q", result.Output)
+	}
+}
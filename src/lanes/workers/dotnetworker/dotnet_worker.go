@@ -1,6 +1,7 @@
 package dotnetworker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -37,7 +38,7 @@ func (worker DotnetPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker DotnetPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker DotnetPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.DotnetTask {
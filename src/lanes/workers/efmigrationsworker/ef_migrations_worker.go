@@ -1,6 +1,7 @@
 package efmigrationsworker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -37,7 +38,7 @@ func (worker EFMigrationsPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker EFMigrationsPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker EFMigrationsPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.EFMigrationTask {
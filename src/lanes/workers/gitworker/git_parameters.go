@@ -1,6 +1,8 @@
 package gitworker
 
 import (
+	"errors"
+
 	"github.com/cjlapao/locally-cli/context/git_component"
 	"github.com/cjlapao/locally-cli/environment"
 	"github.com/cjlapao/locally-cli/mappers"
@@ -11,10 +13,32 @@ type GitParameters struct {
 	Destination string                        `json:"destination,omitempty" yaml:"destination,omitempty"`
 	Clean       bool                          `json:"clean,omitempty" yaml:"clean,omitempty"`
 	Credentials *git_component.GitCredentials `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Ref         string                        `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Depth       int                           `json:"depth,omitempty" yaml:"depth,omitempty"`
+	SparsePaths []string                      `json:"sparsePaths,omitempty" yaml:"sparsePaths,omitempty"`
+	Submodules  bool                          `json:"submodules,omitempty" yaml:"submodules,omitempty"`
+	Update      bool                          `json:"update,omitempty" yaml:"update,omitempty"`
 }
 
 func (c *GitParameters) Validate() bool {
-	return c.RepoUrl != ""
+	return c.ValidateOptions() == nil
+}
+
+// ValidateOptions returns why the parameters are not valid
+func (c *GitParameters) ValidateOptions() error {
+	if c.RepoUrl == "" {
+		return errors.New("repoUrl is required")
+	}
+	if c.Depth < 0 {
+		return errors.New("depth cannot be negative")
+	}
+	for _, path := range c.SparsePaths {
+		if path == "" {
+			return errors.New("sparsePaths cannot have empty paths")
+		}
+	}
+
+	return nil
 }
 
 func (c *GitParameters) Decode() {
@@ -23,4 +47,8 @@ func (c *GitParameters) Decode() {
 	c.RepoUrl = env.Replace(c.RepoUrl)
 	c.Destination = env.Replace(c.Destination)
 	c.Credentials = mappers.DecodeGitCredentials(c.Credentials)
+	c.Ref = env.Replace(c.Ref)
+	for key, value := range c.SparsePaths {
+		c.SparsePaths[key] = env.Replace(value)
+	}
 }
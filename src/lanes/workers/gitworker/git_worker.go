@@ -1,17 +1,16 @@
 package gitworker
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/configuration"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
-	"github.com/cjlapao/locally-cli/git"
+	git_service "github.com/cjlapao/locally-cli/git"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
-	"github.com/cjlapao/locally-cli/mappers"
 	"github.com/cjlapao/locally-cli/notifications"
 
 	"github.com/cjlapao/common-go/helper"
@@ -39,9 +38,9 @@ func (worker GitPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker GitPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker GitPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	config := configuration.Get()
-	git := git.Get()
+	git := git_service.Get()
 
 	result := entities.PipelineWorkerResult{}
 
@@ -71,20 +70,25 @@ func (worker GitPipelineWorker) Run(task *pipeline_component.PipelineTask) entit
 		inputs.Destination = helper.JoinPath(sources, repoFolder)
 	}
 	notify.Debug("Cloning repo %s to %s", inputs.RepoUrl, inputs.Destination)
-	if inputs.Credentials != nil {
-		mappers.DecodeGitCredentials(inputs.Credentials)
-		if err := git.CloneWithCredentials(inputs.RepoUrl, inputs.Destination, inputs.Credentials, inputs.Clean); err != nil {
-			notify.Error(err.Error())
-			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
-		}
-	} else {
-		if err := git.Clone(inputs.RepoUrl, inputs.Destination, inputs.Clean); err != nil {
-			notify.Error(err.Error())
-			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
-		}
-	}
-
-	msg := fmt.Sprintf("Git executed successfully for task %s", task.Name)
+	commit, err := git.CloneWithOptions(inputs.RepoUrl, inputs.Destination, &git_service.CloneOptions{
+		Credentials: inputs.Credentials,
+		Clean:       inputs.Clean,
+		Update:      inputs.Update,
+		Ref:         inputs.Ref,
+		Depth:       inputs.Depth,
+		SparsePaths: inputs.SparsePaths,
+		Submodules:  inputs.Submodules,
+	})
+	if err != nil {
+		notify.Error(err.Error())
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	result.Output = commit
+	result.AddOutput("commit", commit)
+	result.AddOutput("path", inputs.Destination)
+
+	msg := fmt.Sprintf("Git executed successfully for task %s, checked out %s", task.Name, commit)
 	if common.IsDebug() {
 		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
 	}
@@ -103,17 +107,60 @@ func (worker GitPipelineWorker) Validate(task *pipeline_component.PipelineTask)
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("failed validation, %s", err.Error()))
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker GitPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.GitTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	ref := inputs.Ref
+	if ref == "" {
+		ref = "the default branch"
+	}
+	destination := inputs.Destination
+	if destination == "" {
+		destination = fmt.Sprintf("the sources folder as %s", worker.extractRepoName(inputs.RepoUrl))
+	}
+
+	lines := []string{fmt.Sprintf("clone %s at %s to %s", inputs.RepoUrl, ref, destination)}
+	if inputs.Depth > 0 {
+		lines = append(lines, fmt.Sprintf("only the last %v commits", inputs.Depth))
+	}
+	if len(inputs.SparsePaths) > 0 {
+		lines = append(lines, fmt.Sprintf("only the paths %s", strings.Join(inputs.SparsePaths, ", ")))
+	}
+	if inputs.Submodules {
+		lines = append(lines, "with submodules")
+	}
+	switch {
+	case inputs.Update:
+		lines = append(lines, "fetch and fast forward if it was already cloned")
+	case inputs.Clean:
+		lines = append(lines, "delete the destination before cloning")
 	}
 
+	result.Output = strings.Join(lines, "\n")
 	result.State = entities.StateValid
 	return result
 }
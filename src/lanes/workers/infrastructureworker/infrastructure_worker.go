@@ -1,12 +1,14 @@
 package infrastructureworker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
 	"github.com/cjlapao/locally-cli/infrastructure"
 	"github.com/cjlapao/locally-cli/lanes/entities"
@@ -38,7 +40,7 @@ func (worker InfrastructurePipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker InfrastructurePipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker InfrastructurePipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.InfrastructureTask {
@@ -87,6 +89,8 @@ func (worker InfrastructurePipelineWorker) Run(task *pipeline_component.Pipeline
 		return entities.NewPipelineWorkerResultFromError(ErrorInvalidCommand, errors.New("error running infrastructure"))
 	}
 
+	worker.addStackOutputs(&result, inputs.StackName)
+
 	msg := fmt.Sprintf("Infrastructure executed successfully for task %s", task.Name)
 	if common.IsDebug() {
 		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
@@ -121,6 +125,21 @@ func (worker InfrastructurePipelineWorker) Validate(task *pipeline_component.Pip
 	return result
 }
 
+// addStackOutputs publishes the terraform outputs of the stack as task outputs
+func (worker InfrastructurePipelineWorker) addStackOutputs(result *entities.PipelineWorkerResult, stackName string) {
+	context := configuration.Get().GetCurrentContext()
+	if stackName == "" || context == nil || context.EnvironmentVariables == nil {
+		return
+	}
+
+	prefix := fmt.Sprintf("%s.", strings.ToLower(common.EncodeName(stackName)))
+	for key, value := range context.EnvironmentVariables.Terraform {
+		if strings.HasPrefix(key, prefix) {
+			result.AddOutput(strings.TrimPrefix(key, prefix), fmt.Sprintf("%v", value))
+		}
+	}
+}
+
 func (worker InfrastructurePipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*InfrastructureParameters, error) {
 	encoded, err := yaml.Marshal(task.Inputs)
 	if err != nil {
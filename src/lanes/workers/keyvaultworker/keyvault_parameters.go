@@ -1,17 +1,56 @@
 package keyvaultworker
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/cjlapao/locally-cli/environment"
 )
 
+const (
+	CommandSync   = "sync"
+	CommandSet    = "set"
+	CommandDelete = "delete"
+)
+
 type KeyvaultParameters struct {
-	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
-	KeyvaultUrl  string `json:"keyvaultUrl,omitempty" yaml:"keyvaultUrl,omitempty"`
-	Base64Decode bool   `json:"base64Decode,omitempty" yaml:"base64Decode,omitempty"`
+	Name         string            `json:"name,omitempty" yaml:"name,omitempty"`
+	KeyvaultUrl  string            `json:"keyvaultUrl,omitempty" yaml:"keyvaultUrl,omitempty"`
+	Base64Decode bool              `json:"base64Decode,omitempty" yaml:"base64Decode,omitempty"`
+	Command      string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Prefix       string            `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Secrets      []string          `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Values       map[string]string `json:"values,omitempty" yaml:"values,omitempty"`
 }
 
 func (c *KeyvaultParameters) Validate() bool {
-	return c.KeyvaultUrl != ""
+	return c.ValidateOptions() == nil
+}
+
+// ValidateOptions returns why the parameters are not valid
+func (c *KeyvaultParameters) ValidateOptions() error {
+	if c.KeyvaultUrl == "" {
+		return errors.New("keyvaultUrl is required")
+	}
+
+	switch c.Command {
+	case "", CommandSync:
+		if c.Prefix != "" && len(c.Secrets) > 0 {
+			return errors.New("only one of prefix or secrets can be set")
+		}
+	case CommandSet:
+		if len(c.Values) == 0 {
+			return errors.New("set needs the values of the secrets")
+		}
+	case CommandDelete:
+		if len(c.Secrets) == 0 {
+			return errors.New("delete needs the secrets to delete")
+		}
+	default:
+		return fmt.Errorf("invalid command %s, it needs to be one of sync, set or delete", c.Command)
+	}
+
+	return nil
 }
 
 func (c *KeyvaultParameters) Decode() {
@@ -19,4 +58,14 @@ func (c *KeyvaultParameters) Decode() {
 
 	c.KeyvaultUrl = env.Replace(c.KeyvaultUrl)
 	c.Name = env.Replace(c.Name)
+	c.Prefix = env.Replace(c.Prefix)
+	if c.Command == "" {
+		c.Command = CommandSync
+	}
+	for key, value := range c.Secrets {
+		c.Secrets[key] = env.Replace(value)
+	}
+	for key, value := range c.Values {
+		c.Values[key] = env.Replace(value)
+	}
 }
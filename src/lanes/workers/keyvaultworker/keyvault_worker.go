@@ -1,8 +1,11 @@
 package keyvaultworker
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
@@ -35,7 +38,7 @@ func (worker KeyvaultPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker KeyvaultPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker KeyvaultPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.KeyvaultSyncTask {
@@ -58,17 +61,56 @@ func (worker KeyvaultPipelineWorker) Run(task *pipeline_component.PipelineTask)
 
 	inputs.Decode()
 
-	notify.Debug("Starting to sync the keyvault on %s", inputs.KeyvaultUrl)
-	kvSync := azure_keyvault.New(inputs.Name, &azure_keyvault.AzureKeyVaultOptions{
+	kv := azure_keyvault.New(inputs.Name, &azure_keyvault.AzureKeyVaultOptions{
 		KeyVaultUri:  inputs.KeyvaultUrl,
 		DecodeBase64: inputs.Base64Decode,
+		Prefix:       inputs.Prefix,
+		Secrets:      inputs.Secrets,
 	})
 
-	if _, err := kvSync.Sync(); err != nil {
-		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	msg := ""
+	switch inputs.Command {
+	case CommandSet:
+		names := sortedKeys(inputs.Values)
+		for _, name := range names {
+			notify.Debug("Setting secret %s in the keyvault on %s", name, inputs.KeyvaultUrl)
+			if err := kv.SetSecret(name, inputs.Values[name]); err != nil {
+				return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+			}
+		}
+
+		result.Output = strings.Join(names, "\n")
+		result.AddOutput("secrets", strings.Join(names, ","))
+		msg = fmt.Sprintf("Azure KeyVault set %v secrets successfully for task %s", len(names), task.Name)
+	case CommandDelete:
+		for _, name := range inputs.Secrets {
+			notify.Debug("Deleting secret %s from the keyvault on %s", name, inputs.KeyvaultUrl)
+			if err := kv.DeleteSecret(name); err != nil {
+				return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+			}
+		}
+
+		result.Output = strings.Join(inputs.Secrets, "\n")
+		result.AddOutput("secrets", strings.Join(inputs.Secrets, ","))
+		msg = fmt.Sprintf("Azure KeyVault deleted %v secrets successfully for task %s", len(inputs.Secrets), task.Name)
+	default:
+		notify.Debug("Starting to sync the keyvault on %s", inputs.KeyvaultUrl)
+		synced, err := kv.Sync()
+		if err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		}
+
+		keys := make([]string, 0)
+		for key := range synced {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		result.Output = strings.Join(keys, "\n")
+		result.AddOutput("count", strconv.Itoa(len(keys)))
+		msg = fmt.Sprintf("Azure KeyVault sync executed successfully for task %s", task.Name)
 	}
 
-	msg := fmt.Sprintf("Azure KeyVault sync executed successfully for task %s", task.Name)
 	if common.IsDebug() {
 		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
 	}
@@ -87,17 +129,58 @@ func (worker KeyvaultPipelineWorker) Validate(task *pipeline_component.PipelineT
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	if err := inputs.ValidateOptions(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("failed validation, %s", err.Error()))
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker KeyvaultPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.KeyvaultSyncTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	inputs.Decode()
+
+	lines := make([]string, 0)
+	switch inputs.Command {
+	case CommandSet:
+		for _, name := range sortedKeys(inputs.Values) {
+			lines = append(lines, fmt.Sprintf("set secret %s in %s", name, inputs.KeyvaultUrl))
+		}
+	case CommandDelete:
+		for _, name := range inputs.Secrets {
+			lines = append(lines, fmt.Sprintf("delete secret %s from %s", name, inputs.KeyvaultUrl))
+		}
+	default:
+		switch {
+		case len(inputs.Secrets) > 0:
+			lines = append(lines, fmt.Sprintf("sync secrets %s from %s", strings.Join(inputs.Secrets, ", "), inputs.KeyvaultUrl))
+		case inputs.Prefix != "":
+			lines = append(lines, fmt.Sprintf("sync secrets starting with %s from %s", inputs.Prefix, inputs.KeyvaultUrl))
+		default:
+			lines = append(lines, fmt.Sprintf("sync all secrets from %s", inputs.KeyvaultUrl))
+		}
+	}
+	if inputs.Base64Decode {
+		lines = append(lines, "values are base64 encoded in the keyvault")
 	}
 
+	result.Output = strings.Join(lines, "\n")
 	result.State = entities.StateValid
 	return result
 }
@@ -115,3 +198,13 @@ func (worker KeyvaultPipelineWorker) parseParameters(task *pipeline_component.Pi
 
 	return &inputs, nil
 }
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
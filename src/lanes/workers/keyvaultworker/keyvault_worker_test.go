@@ -0,0 +1,48 @@
+package keyvaultworker
+
+import (
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestKeyvaultPipelineWorker_Validate(t *testing.T) {
+	worker := KeyvaultPipelineWorker{}.New()
+	url := "https://example.vault.azure.net"
+	tests := []struct {
+		name   string
+		inputs map[string]interface{}
+		want   entities.PipelineWorkerResultState
+	}{
+		{"sync", map[string]interface{}{"keyvaultUrl": url}, entities.StateValid},
+		{"no url", map[string]interface{}{}, entities.StateErrored},
+		{"sync prefix and secrets", map[string]interface{}{"keyvaultUrl": url, "prefix": "api-", "secrets": []interface{}{"key"}}, entities.StateErrored},
+		{"set", map[string]interface{}{"keyvaultUrl": url, "command": "set", "values": map[string]interface{}{"key": "value"}}, entities.StateValid},
+		{"set without values", map[string]interface{}{"keyvaultUrl": url, "command": "set"}, entities.StateErrored},
+		{"delete without secrets", map[string]interface{}{"keyvaultUrl": url, "command": "delete"}, entities.StateErrored},
+		{"invalid command", map[string]interface{}{"keyvaultUrl": url, "command": "purge"}, entities.StateErrored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := worker.Validate(&pipeline_component.PipelineTask{Name: "keyvault", Type: pipeline_component.KeyvaultSyncTask, Inputs: tt.inputs})
+			if result.State != tt.want {
+				t.Errorf("Validate() = %v, want %v, %v", result.State, tt.want, result.Error)
+			}
+		})
+	}
+}
+
+func TestKeyvaultPipelineWorker_Plan(t *testing.T) {
+	worker := KeyvaultPipelineWorker{}.New().(KeyvaultPipelineWorker)
+	result := worker.Plan(&pipeline_component.PipelineTask{Name: "keyvault", Type: pipeline_component.KeyvaultSyncTask, Inputs: map[string]interface{}{
+		"keyvaultUrl": "https://example.vault.azure.net",
+		"command":     "set",
+		"values":      map[string]interface{}{"client-secret": "s3cr3t"},
+	}})
+
+	if result.State != entities.StateValid || result.Output != "set secret client-secret in https://example.vault.azure.net" {
+		t.Errorf("unexpected plan %v %q", result.State, result.Output)
+	}
+}
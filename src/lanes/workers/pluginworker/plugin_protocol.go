@@ -0,0 +1,45 @@
+package pluginworker
+
+import (
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+)
+
+const (
+	PROTOCOL_VERSION  = 1
+	EXECUTABLE_PREFIX = "locally-worker-"
+)
+
+const (
+	ActionRun      = "run"
+	ActionValidate = "validate"
+	ActionPlan     = "plan"
+)
+
+const (
+	MessageLog    = "log"
+	MessageResult = "result"
+)
+
+// PluginRequest is written as json to the standard input of the plugin, the placeholders of the
+// task are already replaced
+type PluginRequest struct {
+	Version int                              `json:"version"`
+	Action  string                           `json:"action"`
+	Context string                           `json:"context,omitempty"`
+	Task    *pipeline_component.PipelineTask `json:"task"`
+}
+
+// PluginMessage is a line the plugin writes to its standard output, log lines are shown as they
+// arrive and the result line is the result of the task, lines that are not json are shown as
+// information
+type PluginMessage struct {
+	Type      string            `json:"type"`
+	Level     string            `json:"level,omitempty"`
+	Message   string            `json:"message,omitempty"`
+	State     string            `json:"state,omitempty"`
+	Output    string            `json:"output,omitempty"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	ErrorCode string            `json:"errorCode,omitempty"`
+	ExitCode  string            `json:"exitCode,omitempty"`
+}
@@ -0,0 +1,302 @@
+package pluginworker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/executer"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+var notify = notifications.Get()
+
+const (
+	ErrorNotFound  = "404"
+	ErrorExecuting = "500"
+)
+
+// PluginPipelineWorker runs the tasks which type is not built in with an external executable, the
+// task is sent as json to its standard input and the result is read from its standard output
+type PluginPipelineWorker struct {
+	name string
+}
+
+// pluginExecutable is the command that runs the plugin of a task type
+type pluginExecutable struct {
+	Path      string
+	Arguments []string
+}
+
+func (worker PluginPipelineWorker) New() interfaces.PipelineWorker {
+	return PluginPipelineWorker{
+		name: "plugin.worker",
+	}
+}
+
+func (worker PluginPipelineWorker) Name() string {
+	return worker.name
+}
+
+func (worker PluginPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	if !task.Type.IsCustom() {
+		notify.Debug("[%s] %s: This is not a task for me, bye...", worker.name, task.Name)
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	notify.Debug("[%s] picked up task %s to work on", worker.name, task.Name)
+
+	plugin, err := findExecutable(task.Type.String())
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	result = worker.call(ctx, plugin, ActionRun, task)
+	if result.State == entities.StateErrored {
+		return result
+	}
+	if result.State != entities.StateExecuted {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, fmt.Errorf("plugin %s returned %s when running task %s", plugin.Path, result.State.String(), task.Name))
+	}
+
+	msg := fmt.Sprintf("Plugin %s executed successfully for task %s", task.Type.String(), task.Name)
+	if common.IsDebug() {
+		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+	}
+	notify.Success(msg)
+
+	return result
+}
+
+func (worker PluginPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if !task.Type.IsCustom() {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	plugin, err := findExecutable(task.Type.String())
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	result = worker.call(context.Background(), plugin, ActionValidate, task)
+	switch result.State {
+	case entities.StateErrored:
+		return result
+	case entities.StateValid, entities.StateIgnored:
+		// plugins that do not validate their tasks are valid as long as they exist
+		return entities.PipelineWorkerResult{State: entities.StateValid}
+	default:
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, fmt.Errorf("plugin %s returned %s when validating task %s", plugin.Path, result.State.String(), task.Name))
+	}
+}
+
+func (worker PluginPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if !task.Type.IsCustom() {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	plugin, err := findExecutable(task.Type.String())
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	result = worker.call(context.Background(), plugin, ActionPlan, task)
+	if result.State == entities.StateValid {
+		result.Output = fmt.Sprintf("run plugin %s\n%s", plugin.Path, result.Output)
+		result.Output = strings.TrimRight(result.Output, "\n")
+	}
+
+	return result
+}
+
+// call sends the task to the plugin and reads its messages, a plugin that exits without a result
+// errors unless it exited successfully, in which case it is treated as ignored
+func (worker PluginPipelineWorker) call(ctx context.Context, plugin *pluginExecutable, action string, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	resolved, err := resolveTask(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	request := PluginRequest{
+		Version: PROTOCOL_VERSION,
+		Action:  action,
+		Task:    resolved,
+	}
+	if context := configuration.Get().GetCurrentContext(); context != nil {
+		request.Context = context.Name
+	}
+
+	content, err := json.Marshal(request)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	var message *PluginMessage
+	output, err := executer.ExecuteAndStreamContext(ctx, "", bytes.NewReader(content), func(line string) {
+		if received := worker.handleLine(line); received != nil {
+			message = received
+		}
+	}, plugin.Path, plugin.Arguments...)
+
+	if message == nil {
+		if err != nil {
+			if ctx.Err() != nil {
+				return entities.NewPipelineWorkerResultFromError(ErrorExecuting, ctx.Err())
+			}
+			result := entities.NewPipelineWorkerResultFromError(ErrorExecuting, fmt.Errorf("plugin %s failed without a result, %s %s", plugin.Path, err.Error(), strings.TrimSpace(output.StdErr)))
+			result.ExitCode = fmt.Sprintf("%v", output.ExitCode)
+			return result
+		}
+		return entities.PipelineWorkerResult{State: entities.StateIgnored}
+	}
+
+	result := newResult(message)
+	if err != nil && result.State != entities.StateErrored {
+		result = entities.NewPipelineWorkerResultFromError(ErrorExecuting, fmt.Errorf("plugin %s returned %s but failed, %s", plugin.Path, message.State, err.Error()))
+		result.ExitCode = fmt.Sprintf("%v", output.ExitCode)
+	}
+
+	return result
+}
+
+// handleLine shows the log lines of the plugin and returns the result message
+func (worker PluginPipelineWorker) handleLine(line string) *PluginMessage {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	var message PluginMessage
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") || json.Unmarshal([]byte(line), &message) != nil {
+		notify.Info("%s", line)
+		return nil
+	}
+
+	switch message.Type {
+	case MessageResult:
+		return &message
+	case MessageLog:
+		switch strings.ToLower(message.Level) {
+		case "debug":
+			notify.Debug("%s", message.Message)
+		case "warning", "warn":
+			notify.Warning("%s", message.Message)
+		case "error":
+			notify.Error("%s", message.Message)
+		case "success":
+			notify.Success("%s", message.Message)
+		default:
+			notify.Info("%s", message.Message)
+		}
+	default:
+		notify.Info("%s", line)
+	}
+
+	return nil
+}
+
+func newResult(message *PluginMessage) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{
+		Output:    message.Output,
+		Outputs:   message.Outputs,
+		ErrorCode: message.ErrorCode,
+		ExitCode:  message.ExitCode,
+	}
+	result.State.FromString(strings.ToLower(message.State))
+
+	if message.Error != "" {
+		result.Error = errors.New(message.Error)
+	}
+	if result.State == entities.StateErrored && result.Error == nil {
+		result.Error = errors.New("plugin returned an error")
+	}
+	if message.Error != "" && result.State != entities.StateErrored {
+		result.State = entities.StateErrored
+	}
+
+	return result
+}
+
+// findExecutable returns the plugin declared for the task type in the global configuration or the
+// locally-worker-<type> executable in the path
+func findExecutable(taskType string) (*pluginExecutable, error) {
+	config := configuration.Get()
+	if config.GlobalConfiguration != nil {
+		for _, worker := range config.GlobalConfiguration.PipelineWorkers {
+			if strings.EqualFold(worker.Type, taskType) {
+				return &pluginExecutable{
+					Path:      environment.Get().Replace(worker.Path),
+					Arguments: worker.Arguments,
+				}, nil
+			}
+		}
+	}
+
+	path, err := exec.LookPath(EXECUTABLE_PREFIX + taskType)
+	if err != nil {
+		return nil, fmt.Errorf("there is no worker for tasks of type %s, add %s%s to the path or declare it in the pipelineWorkers of the global configuration", taskType, EXECUTABLE_PREFIX, taskType)
+	}
+
+	return &pluginExecutable{
+		Path:      path,
+		Arguments: []string{},
+	}, nil
+}
+
+// resolveTask returns a copy of the task with the placeholders of its inputs, body and working
+// directory replaced
+func resolveTask(task *pipeline_component.PipelineTask) (*pipeline_component.PipelineTask, error) {
+	content, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved pipeline_component.PipelineTask
+	if err := json.Unmarshal(content, &resolved); err != nil {
+		return nil, err
+	}
+
+	env := environment.Get()
+	resolved.Body = env.ReplaceText(resolved.Body)
+	resolved.WorkingDirectory = env.Replace(resolved.WorkingDirectory)
+	for key, value := range resolved.Inputs {
+		resolved.Inputs[key] = resolveValue(value)
+	}
+
+	return &resolved, nil
+}
+
+func resolveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return environment.Get().Replace(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = resolveValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = resolveValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
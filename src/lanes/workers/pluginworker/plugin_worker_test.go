@@ -0,0 +1,119 @@
+package pluginworker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"gopkg.in/yaml.v3"
+)
+
+const testPlugin = `#!/bin/sh
+request=$(cat)
+case "$request" in
+  *'"action":"validate"'*)
+    case "$request" in
+      *'"target"'*) echo '{"type":"result","state":"valid"}' ;;
+      *) echo '{"type":"result","state":"errored","error":"target is required"}' ;;
+    esac
+    ;;
+  *'"action":"plan"'*)
+    echo '{"type":"result","state":"valid","output":"deploy the target"}'
+    ;;
+  *)
+    echo '{"type":"log","level":"info","message":"deploying"}'
+    echo 'plain output'
+    echo '{"type":"result","state":"executed","output":"done","outputs":{"version":"1.2.3"}}'
+    ;;
+esac
+`
+
+func setupPlugin(t *testing.T, name string, content string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+
+	folder := t.TempDir()
+	if err := os.WriteFile(filepath.Join(folder, EXECUTABLE_PREFIX+name), []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", folder+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPluginPipelineWorker(t *testing.T) {
+	setupPlugin(t, "deploy", testPlugin)
+	worker := PluginPipelineWorker{}.New()
+	taskType := pipeline_component.GetPipelineTaskType("deploy")
+
+	result := worker.Validate(&pipeline_component.PipelineTask{Name: "deploy", Type: taskType})
+	if result.State != entities.StateErrored || !strings.Contains(result.Error.Error(), "target is required") {
+		t.Errorf("expected the plugin to fail the validation, got %v", result.State)
+	}
+
+	task := &pipeline_component.PipelineTask{Name: "deploy", Type: taskType, Inputs: map[string]interface{}{"target": "staging"}}
+	if result := worker.Validate(task); result.State != entities.StateValid {
+		t.Errorf("expected the task to be valid, got %v %v", result.State, result.Error)
+	}
+
+	planner := worker.(PluginPipelineWorker)
+	if result := planner.Plan(task); result.State != entities.StateValid || !strings.Contains(result.Output, "deploy the target") {
+		t.Errorf("unexpected plan %v %q", result.State, result.Output)
+	}
+
+	result = worker.Run(context.Background(), task)
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the plugin to run, %v", result.Error)
+	}
+	if result.Output != "done" || result.Outputs["version"] != "1.2.3" {
+		t.Errorf("unexpected result %q %v", result.Output, result.Outputs)
+	}
+
+	if result := worker.Run(context.Background(), &pipeline_component.PipelineTask{Name: "bash", Type: pipeline_component.BashTask}); result.State != entities.StateIgnored {
+		t.Errorf("expected built in tasks to be ignored, got %v", result.State)
+	}
+}
+
+func TestPluginPipelineWorker_Errors(t *testing.T) {
+	setupPlugin(t, "broken", "#!/bin/sh\ncat > /dev/null\necho 'something went wrong' >&2\nexit 2\n")
+	worker := PluginPipelineWorker{}.New()
+
+	result := worker.Run(context.Background(), &pipeline_component.PipelineTask{Name: "broken", Type: pipeline_component.GetPipelineTaskType("broken")})
+	if result.State != entities.StateErrored || result.ExitCode != "2" || !strings.Contains(result.Error.Error(), "something went wrong") {
+		t.Errorf("expected the plugin to fail with its error, got %v %v", result.State, result.Error)
+	}
+
+	result = worker.Validate(&pipeline_component.PipelineTask{Name: "missing", Type: pipeline_component.GetPipelineTaskType("not-installed")})
+	if result.State != entities.StateErrored || !strings.Contains(result.Error.Error(), EXECUTABLE_PREFIX+"not-installed") {
+		t.Errorf("expected a missing plugin to fail the validation, got %v", result.State)
+	}
+}
+
+func TestPipelineTaskType_Custom(t *testing.T) {
+	var task pipeline_component.PipelineTask
+	if err := yaml.Unmarshal([]byte("name: notify\ntype: slack\n"), &task); err != nil {
+		t.Fatal(err)
+	}
+	if !task.Type.IsCustom() || task.Type.String() != "slack" {
+		t.Fatalf("expected a custom slack task type, got %v", task.Type.String())
+	}
+	if pipeline_component.GetPipelineTaskType("slack") != task.Type {
+		t.Errorf("expected the same type for the same name")
+	}
+	if pipeline_component.BashTask.IsCustom() {
+		t.Errorf("expected built in types not to be custom")
+	}
+
+	content, err := yaml.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "type: slack") {
+		t.Errorf("expected the type name to be kept, got %s", content)
+	}
+}
@@ -0,0 +1,38 @@
+package proxyworker
+
+import (
+	"strings"
+
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+const (
+	CommandGenerate = "generate"
+	CommandUp       = "up"
+	CommandDown     = "down"
+	CommandReload   = "reload"
+)
+
+var commands = []string{CommandGenerate, CommandUp, CommandDown, CommandReload}
+
+type ProxyParameters struct {
+	Command  string `json:"command,omitempty" yaml:"command,omitempty"`
+	Generate bool   `json:"generate,omitempty" yaml:"generate,omitempty"`
+	Build    bool   `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+func (c *ProxyParameters) Validate() bool {
+	for _, command := range commands {
+		if strings.EqualFold(c.Command, command) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ProxyParameters) Decode() {
+	env := environment.Get()
+
+	c.Command = strings.ToLower(strings.TrimSpace(env.Replace(c.Command)))
+}
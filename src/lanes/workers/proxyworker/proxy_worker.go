@@ -0,0 +1,185 @@
+package proxyworker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/caddy"
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+	"github.com/cjlapao/locally-cli/system"
+
+	"gopkg.in/yaml.v3"
+)
+
+var notify = notifications.Get()
+
+const (
+	ErrorInvalidParameters = "400"
+	ErrorExecuting         = "500"
+)
+
+type ProxyPipelineWorker struct {
+	name string
+}
+
+func (worker ProxyPipelineWorker) New() interfaces.PipelineWorker {
+	return ProxyPipelineWorker{
+		name: "proxy.worker",
+	}
+}
+
+func (worker ProxyPipelineWorker) Name() string {
+	return worker.name
+}
+
+func (worker ProxyPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	if task.Type != pipeline_component.ProxyTask {
+		notify.Debug("[%s] %s: This is not a task for me, bye...", worker.name, task.Name)
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	notify.Debug("[%s] picked up task %s to work on", worker.name, task.Name)
+
+	validationResult := worker.Validate(task)
+	if validationResult.State != entities.StateValid {
+		return validationResult
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	caddySvc := caddy.Get()
+	caddySvc.CheckForCaddy(true)
+
+	switch inputs.Command {
+	case CommandGenerate:
+		err = worker.generate(caddySvc)
+	case CommandUp:
+		if inputs.Generate {
+			err = worker.generate(caddySvc)
+		}
+		if err == nil && inputs.Build {
+			err = caddySvc.BuildContainer()
+		}
+		if err == nil {
+			err = caddySvc.ContainerUp()
+		}
+	case CommandDown:
+		err = caddySvc.ContainerDown()
+	case CommandReload:
+		// the caddy files are copied into the proxy image so it needs to be rebuilt to pick
+		// up the changes
+		err = worker.generate(caddySvc)
+		if err == nil {
+			err = caddySvc.RebuildContainer()
+		}
+	}
+
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	msg := fmt.Sprintf("Proxy %s executed successfully for task %s", inputs.Command, task.Name)
+	if common.IsDebug() {
+		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+	}
+	notify.Success(msg)
+
+	result.State = entities.StateExecuted
+	return result
+}
+
+func (worker ProxyPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.ProxyTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+	if !inputs.Validate() {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("invalid proxy command %s, it needs to be one of %s", inputs.Command, strings.Join(commands, ", ")))
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker ProxyPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.ProxyTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	lines := make([]string, 0)
+	switch inputs.Command {
+	case CommandGenerate:
+		lines = append(lines, "generate the proxy docker and caddy files")
+	case CommandUp:
+		if inputs.Generate {
+			lines = append(lines, "generate the proxy docker and caddy files")
+		}
+		if inputs.Build {
+			lines = append(lines, "build the proxy container")
+		}
+		lines = append(lines, "bring up the proxy container")
+	case CommandDown:
+		lines = append(lines, "bring down the proxy container and remove its image")
+	case CommandReload:
+		lines = append(lines, "generate the proxy docker and caddy files and rebuild the proxy container")
+	default:
+		lines = append(lines, fmt.Sprintf("unknown proxy command %s", inputs.Command))
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker ProxyPipelineWorker) generate(caddySvc *caddy.CaddyService) error {
+	system.Get().CheckFolders(false)
+	if err := caddySvc.GenerateDockerFiles(); err != nil {
+		return err
+	}
+
+	return caddySvc.GenerateCaddyFiles()
+}
+
+func (worker ProxyPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*ProxyParameters, error) {
+	encoded, err := yaml.Marshal(task.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	var inputs ProxyParameters
+	err = yaml.Unmarshal(encoded, &inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inputs, nil
+}
@@ -0,0 +1,81 @@
+package proxyworker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	locally_entities "github.com/cjlapao/locally-cli/entities"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func TestProxyPipelineWorker_Reload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake docker compose is a shell script")
+	}
+
+	root := t.TempDir()
+	output := filepath.Join(root, "output")
+	caddyFile := filepath.Join(output, common.CADDY_PATH, "Caddyfile")
+
+	// the fake docker compose keeps the Caddyfile found by its first call, that is what the
+	// rebuilt image would get
+	calls := filepath.Join(root, "calls.log")
+	built := filepath.Join(root, "Caddyfile.built")
+	compose := filepath.Join(root, "docker-compose")
+	script := "#!/bin/sh\n[ -f " + built + " ] || cp " + caddyFile + " " + built + "\necho \"$@\" >> " + calls + "\n"
+	if err := os.WriteFile(compose, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := configuration.Get()
+	config.GlobalConfiguration.Network = &configuration.Network{DomainName: "locally.internal"}
+	config.GlobalConfiguration.Cors = &configuration.Cors{AllowedMethods: "GET", AllowedHeaders: "*"}
+	config.GlobalConfiguration.Tools.Docker = &context_entities.DockerTool{ComposerPath: compose}
+	config.GlobalConfiguration.Tools.Checked.CaddyChecked = true
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{
+		{Name: "proxy-test", Configuration: &context_entities.ContextConfiguration{
+			OutputPath:           output,
+			RootURI:              "api",
+			LocallyConfigService: &locally_entities.LocallyConfigService{Url: "config.locally.internal", ReverseProxyUrl: "http://config:5000"},
+		}},
+	}
+	config.GlobalConfiguration.CurrentContext = "proxy-test"
+
+	if err := os.MkdirAll(filepath.Dir(caddyFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(caddyFile, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := ProxyPipelineWorker{}.New()
+	result := worker.Run(context.Background(), &pipeline_component.PipelineTask{Name: "reload", Type: pipeline_component.ProxyTask, Inputs: map[string]interface{}{"command": "reload"}})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the proxy to be reloaded, %v", result.Error)
+	}
+
+	content, err := os.ReadFile(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "api.locally.internal {") {
+		t.Errorf("the Caddyfile was not generated before rebuilding, got %q", content)
+	}
+
+	log, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "build") || !strings.Contains(string(log), "up") {
+		t.Errorf("expected the proxy container to be rebuilt, got %q", log)
+	}
+}
@@ -1,21 +1,166 @@
 package sqlworker
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/cjlapao/locally-cli/environment"
+
+	"github.com/cjlapao/common-go/helper"
+)
+
+const (
+	EngineSqlServer = "sqlserver"
+	EnginePostgres  = "postgres"
+	EngineMySql     = "mysql"
+	EngineSqlite    = "sqlite"
 )
 
+const (
+	ModeExecute = "exec"
+	ModeScalar  = "scalar"
+	ModeQuery   = "query"
+)
+
+const (
+	DEFAULT_BATCH_SEPARATOR = "GO"
+)
+
+// engineAliases maps the engine names accepted in the inputs to the driver names
+var engineAliases = map[string]string{
+	"":           EngineSqlServer,
+	"sqlserver":  EngineSqlServer,
+	"mssql":      EngineSqlServer,
+	"postgres":   EnginePostgres,
+	"postgresql": EnginePostgres,
+	"mysql":      EngineMySql,
+	"mariadb":    EngineMySql,
+	"sqlite":     EngineSqlite,
+	"sqlite3":    EngineSqlite,
+}
+
+// GetDriverName returns the database/sql driver of an engine name, the drivers of all the
+// supported engines are registered by this package
+func GetDriverName(engine string) (string, bool) {
+	driver, ok := engineAliases[strings.ToLower(strings.TrimSpace(engine))]
+	return driver, ok
+}
+
 type SqlParameters struct {
-	ConnectionString string `json:"connectionString,omitempty" yaml:"connectionString,omitempty"`
-	Query            string `json:"query,omitempty" yaml:"query,omitempty"`
+	Engine           string   `json:"engine,omitempty" yaml:"engine,omitempty"`
+	ConnectionString string   `json:"connectionString,omitempty" yaml:"connectionString,omitempty"`
+	Query            string   `json:"query,omitempty" yaml:"query,omitempty"`
+	ScriptFiles      []string `json:"scriptFiles,omitempty" yaml:"scriptFiles,omitempty"`
+	BatchSeparator   string   `json:"batchSeparator,omitempty" yaml:"batchSeparator,omitempty"`
+	Transaction      bool     `json:"transaction,omitempty" yaml:"transaction,omitempty"`
+	Mode             string   `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Scalar           bool     `json:"scalar,omitempty" yaml:"scalar,omitempty"`
 }
 
-func (c *SqlParameters) Validate() bool {
-	return c.ConnectionString != "" && c.Query != ""
+func (c *SqlParameters) Validate() error {
+	if c.ConnectionString == "" {
+		return fmt.Errorf("connectionString is required")
+	}
+	if c.Query == "" && len(c.ScriptFiles) == 0 {
+		return fmt.Errorf("a query or scriptFiles are required")
+	}
+	// the engine can come from a step output that is only known when the task runs, the run
+	// checks it again
+	if _, ok := GetDriverName(c.Engine); !ok && !strings.Contains(c.Engine, "${{") {
+		return fmt.Errorf("invalid engine %s, it needs to be one of sqlserver, postgres, mysql or sqlite", c.Engine)
+	}
+	switch strings.ToLower(c.Mode) {
+	case "", ModeExecute, ModeScalar, ModeQuery:
+	default:
+		return fmt.Errorf("invalid mode %s, it needs to be one of exec, scalar or query", c.Mode)
+	}
+
+	return nil
 }
 
 func (c *SqlParameters) Decode() {
 	env := environment.Get()
 
+	c.Engine = env.Replace(c.Engine)
+	if driver, ok := GetDriverName(c.Engine); ok {
+		c.Engine = driver
+	}
 	c.ConnectionString = env.Replace(c.ConnectionString)
 	c.Query = env.Replace(c.Query)
+	for i, file := range c.ScriptFiles {
+		c.ScriptFiles[i] = env.Replace(file)
+	}
+	if c.BatchSeparator == "" {
+		c.BatchSeparator = DEFAULT_BATCH_SEPARATOR
+	}
+
+	c.Mode = strings.ToLower(c.Mode)
+	if c.Mode == "" {
+		c.Mode = ModeExecute
+		if c.Scalar {
+			c.Mode = ModeScalar
+		}
+	}
+}
+
+// Batches returns the batches to execute, first the ones of the query and then the ones of each
+// script file, variables are replaced in the scripts as well
+func (c *SqlParameters) Batches() ([]string, error) {
+	env := environment.Get()
+	result := splitBatches(c.Query, c.BatchSeparator)
+	for _, file := range c.ScriptFiles {
+		if !helper.FileExists(file) {
+			return nil, fmt.Errorf("script file %s was not found", file)
+		}
+
+		content, err := helper.ReadFromFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, splitBatches(env.Replace(string(content)), c.BatchSeparator)...)
+	}
+
+	return result, nil
+}
+
+// splitBatches splits a script on the lines that only have the batch separator, like the GO of
+// sqlcmd, the separator can be followed by a count to run the batch more than once
+func splitBatches(script string, separator string) []string {
+	result := make([]string, 0)
+	if strings.TrimSpace(script) == "" {
+		return result
+	}
+
+	separatorRegex := regexp.MustCompile(fmt.Sprintf(`(?i)^\s*%s(?:\s+(\d+))?\s*;?\s*$`, regexp.QuoteMeta(separator)))
+	current := make([]string, 0)
+	flush := func(count int) {
+		batch := strings.TrimSpace(strings.Join(current, "\n"))
+		current = make([]string, 0)
+		if batch == "" {
+			return
+		}
+		for i := 0; i < count; i++ {
+			result = append(result, batch)
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(script, "\r\n", "\n"), "\n") {
+		match := separatorRegex.FindStringSubmatch(line)
+		if match == nil {
+			current = append(current, line)
+			continue
+		}
+
+		count := 1
+		if match[1] != "" {
+			count, _ = strconv.Atoi(match[1])
+		}
+		flush(count)
+	}
+	flush(1)
+
+	return result
 }
@@ -3,17 +3,24 @@ package sqlworker
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/cjlapao/locally-cli/common"
 	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
 	"github.com/cjlapao/locally-cli/lanes/entities"
 	"github.com/cjlapao/locally-cli/lanes/interfaces"
 	"github.com/cjlapao/locally-cli/notifications"
 
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 	_ "github.com/microsoft/go-mssqldb"
 	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite"
 )
 
 var notify = notifications.Get()
@@ -39,7 +46,7 @@ func (worker SqlPipelineWorker) Name() string {
 	return worker.name
 }
 
-func (worker SqlPipelineWorker) Run(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+func (worker SqlPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 
 	if task.Type != pipeline_component.SqlTask {
@@ -61,44 +68,119 @@ func (worker SqlPipelineWorker) Run(task *pipeline_component.PipelineTask) entit
 	}
 
 	inputs.Decode()
+	if _, ok := GetDriverName(inputs.Engine); !ok {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, fmt.Errorf("invalid engine %s, it needs to be one of sqlserver, postgres, mysql or sqlite", inputs.Engine))
+	}
+
+	batches, err := inputs.Batches()
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+	if len(batches) == 0 {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, errors.New("there are no statements to run in the query or script files"))
+	}
 
-	notify.Debug("SQL Query: %s", inputs.Query)
+	notify.Debug("SQL Engine: %s, %s batches", inputs.Engine, fmt.Sprintf("%d", len(batches)))
 
 	// Create connection pool
-	db, err := sql.Open("sqlserver", inputs.ConnectionString)
+	db, err := sql.Open(inputs.Engine, inputs.ConnectionString)
 	if err != nil {
 		return entities.NewPipelineWorkerResultFromError(ErrorInvalidConnection, err)
 	}
 
-	ctx := context.Background()
+	defer db.Close()
+
 	err = db.PingContext(ctx)
 	if err != nil {
 		return entities.NewPipelineWorkerResultFromError(ErrorCannotConnect, err)
 	}
 
-	r, err := db.ExecContext(ctx, inputs.Query)
-	if err != nil {
-		return entities.NewPipelineWorkerResultFromError(ErrorFailedExecution, err)
+	var executor sqlExecutor = db
+	var tx *sql.Tx
+	if inputs.Transaction {
+		tx, err = db.BeginTx(ctx, nil)
+		if err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorFailedExecution, err)
+		}
+		executor = tx
 	}
 
-	affected, err := r.RowsAffected()
+	output, message, err := worker.execute(ctx, executor, inputs.Mode, batches)
 	if err != nil {
+		if tx != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				notify.Warning("There was an error rolling back the transaction of task %s, %s", task.Name, rollbackErr.Error())
+			}
+		}
 		return entities.NewPipelineWorkerResultFromError(ErrorFailedExecution, err)
 	}
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorFailedExecution, err)
+		}
+	}
 
-	msg := fmt.Sprintf("Sql executed successfully for task %s, affected %s rows", task.Name, fmt.Sprintf("%d", affected))
+	msg := fmt.Sprintf("Sql executed successfully for task %s, %s", task.Name, message)
 	if common.IsDebug() {
 		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
-
 	}
 
 	notify.Success(msg)
 
+	result.Output = output
 	result.State = entities.StateExecuted
 
 	return result
 }
 
+// execute runs the batches in order, in scalar and query mode the last batch is the one that
+// returns the output and the previous ones are executed
+func (worker SqlPipelineWorker) execute(ctx context.Context, executor sqlExecutor, mode string, batches []string) (string, string, error) {
+	statements := batches
+	if mode != ModeExecute && len(batches) > 0 {
+		statements = batches[:len(batches)-1]
+	}
+
+	affected := int64(0)
+	for _, batch := range statements {
+		r, err := executor.ExecContext(ctx, batch)
+		if err != nil {
+			return "", "", err
+		}
+		// not all drivers know the affected rows of every statement
+		if rows, err := r.RowsAffected(); err == nil {
+			affected += rows
+		}
+	}
+
+	switch mode {
+	case ModeScalar:
+		var value sql.NullString
+		if err := executor.QueryRowContext(ctx, batches[len(batches)-1]).Scan(&value); err != nil {
+			return "", "", err
+		}
+		return value.String, "returned a scalar value", nil
+	case ModeQuery:
+		rows, err := executor.QueryContext(ctx, batches[len(batches)-1])
+		if err != nil {
+			return "", "", err
+		}
+		defer rows.Close()
+
+		values, err := readRows(rows)
+		if err != nil {
+			return "", "", err
+		}
+		content, err := json.Marshal(values)
+		if err != nil {
+			return "", "", err
+		}
+		return string(content), fmt.Sprintf("returned %d rows", len(values)), nil
+	default:
+		return "", fmt.Sprintf("affected %d rows", affected), nil
+	}
+}
+
 func (worker SqlPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
 	result := entities.PipelineWorkerResult{}
 	if task.Type != pipeline_component.SqlTask {
@@ -106,17 +188,54 @@ func (worker SqlPipelineWorker) Validate(task *pipeline_component.PipelineTask)
 		return result
 	}
 
-	validationResult, err := worker.parseParameters(task)
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+	if err := inputs.Validate(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker SqlPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.SqlTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
 	if err != nil {
-		result.State = entities.StateErrored
-		result.Error = err
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	action := "execute"
+	switch inputs.Mode {
+	case ModeScalar:
+		action = "query a scalar value with"
+	case ModeQuery:
+		action = "query rows with"
+	}
+	if inputs.Transaction {
+		action = fmt.Sprintf("%s, in a transaction,", action)
 	}
 
-	if !validationResult.Validate() {
-		result.State = entities.StateErrored
-		result.Error = errors.New("failed validation")
+	lines := []string{fmt.Sprintf("connect to %s %s", inputs.Engine, maskConnectionString(inputs.ConnectionString))}
+	if inputs.Query != "" {
+		lines = append(lines, fmt.Sprintf("%s:", action), inputs.Query)
+	}
+	for _, file := range inputs.ScriptFiles {
+		lines = append(lines, fmt.Sprintf("%s script %s", action, file))
 	}
 
+	result.Output = strings.Join(lines, "\n")
 	result.State = entities.StateValid
 	return result
 }
@@ -134,3 +253,57 @@ func (worker SqlPipelineWorker) parseParameters(task *pipeline_component.Pipelin
 
 	return &inputs, nil
 }
+
+// sqlExecutor is what both a connection and a transaction can do
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// readRows reads all the rows as a list of column name to value maps
+func readRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	result := make([]map[string]interface{}, 0)
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{})
+		for i, column := range columns {
+			if value, ok := values[i].([]byte); ok {
+				row[column] = string(value)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// maskConnectionString hides the password of url like connection strings, key value connection
+// strings are masked by the environment
+func maskConnectionString(connectionString string) string {
+	parsed, err := url.Parse(connectionString)
+	if err != nil || parsed.User == nil {
+		return connectionString
+	}
+	if _, ok := parsed.User.Password(); !ok {
+		return connectionString
+	}
+
+	parsed.User = url.UserPassword(parsed.User.Username(), environment.MASK)
+	return strings.Replace(parsed.String(), url.QueryEscape(environment.MASK), environment.MASK, 1)
+}
@@ -0,0 +1,63 @@
+package templateworker
+
+import (
+	"strings"
+
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+// templateExtensions are removed from the name of the files rendered into a folder, so
+// appsettings.Local.json.tmpl is written as appsettings.Local.json
+var templateExtensions = []string{".tmpl", ".tpl", ".template"}
+
+type TemplateParameters struct {
+	Source           string                 `json:"source,omitempty" yaml:"source,omitempty"`
+	Destination      string                 `json:"destination,omitempty" yaml:"destination,omitempty"`
+	WorkingDirectory string                 `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
+	GoTemplate       bool                   `json:"goTemplate,omitempty" yaml:"goTemplate,omitempty"`
+	Values           map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+func (c *TemplateParameters) Validate() bool {
+	if c.Source == "" || c.Destination == "" {
+		return false
+	}
+
+	return true
+}
+
+func (c *TemplateParameters) Decode() {
+	env := environment.Get()
+
+	c.Source = strings.TrimSpace(env.Replace(c.Source))
+	c.Destination = strings.TrimSpace(env.Replace(c.Destination))
+	c.WorkingDirectory = env.Replace(c.WorkingDirectory)
+	for key, value := range c.Values {
+		c.Values[key] = decodeValue(value)
+	}
+}
+
+// decodeValue replaces the variables in a value, maps and lists are copied so the values of the
+// configuration are not changed
+func decodeValue(value interface{}) interface{} {
+	env := environment.Get()
+
+	switch v := value.(type) {
+	case string:
+		return env.Replace(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, item := range v {
+			result[key] = decodeValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = decodeValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
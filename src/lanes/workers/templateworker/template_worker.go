@@ -0,0 +1,342 @@
+package templateworker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/context/service_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+
+	"github.com/cjlapao/common-go/helper"
+	"gopkg.in/yaml.v3"
+)
+
+var notify = notifications.Get()
+
+const (
+	ErrorInvalidParameters = "400"
+	ErrorNotFound          = "404"
+	ErrorExecuting         = "500"
+)
+
+// TemplateData is what the go templates can use, for example
+// {{ range .Tenants }}{{ .Name }}{{ end }}
+type TemplateData struct {
+	Context         string
+	Tenants         []*context_entities.Tenant
+	BackendServices []*service_component.BackendService
+	SpaServices     []*service_component.SpaService
+	Values          map[string]interface{}
+}
+
+// templateFile is a source file and the path it is rendered to
+type templateFile struct {
+	Source      string
+	Destination string
+}
+
+type TemplatePipelineWorker struct {
+	name string
+}
+
+func (worker TemplatePipelineWorker) New() interfaces.PipelineWorker {
+	return TemplatePipelineWorker{
+		name: "template.worker",
+	}
+}
+
+func (worker TemplatePipelineWorker) Name() string {
+	return worker.name
+}
+
+func (worker TemplatePipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	if task.Type != pipeline_component.TemplateTask {
+		notify.Debug("[%s] %s: This is not a task for me, bye...", worker.name, task.Name)
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	notify.Debug("[%s] picked up task %s to work on", worker.name, task.Name)
+
+	validationResult := worker.Validate(task)
+	if validationResult.State != entities.StateValid {
+		return validationResult
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	files, err := resolveFiles(inputs)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	data := newTemplateData(configuration.Get().GetCurrentContext(), inputs.Values)
+	written := make([]string, 0)
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		}
+
+		if err := renderFile(file, inputs.GoTemplate, data); err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		}
+		notify.Debug("[%s] rendered %s to %s", worker.name, file.Source, file.Destination)
+		written = append(written, file.Destination)
+	}
+
+	msg := fmt.Sprintf("Rendered %v files successfully for task %s", len(written), task.Name)
+	if common.IsDebug() {
+		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+	}
+	notify.Success(msg)
+
+	result.Output = strings.Join(written, "\n")
+	result.AddOutput("files", strings.Join(written, ","))
+	if len(written) == 1 {
+		result.AddOutput("path", written[0])
+	}
+	result.State = entities.StateExecuted
+	return result
+}
+
+func (worker TemplatePipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.TemplateTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	if !inputs.Validate() {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, errors.New("source and destination are required"))
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker TemplatePipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.TemplateTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	lines := make([]string, 0)
+	files, err := resolveFiles(inputs)
+	if err != nil {
+		// the source files can be created by previous steps
+		lines = append(lines, fmt.Sprintf("render %s to %s", inputs.Source, inputs.Destination))
+	}
+	for _, file := range files {
+		lines = append(lines, fmt.Sprintf("render %s to %s", file.Source, file.Destination))
+	}
+	if inputs.GoTemplate {
+		lines = append(lines, "using go templates")
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
+// resolveFiles returns the files matching the source and where they are rendered, when the source
+// matches a single file and the destination is not a folder the destination is the file path,
+// otherwise the files are rendered into the destination folder
+func resolveFiles(inputs *TemplateParameters) ([]templateFile, error) {
+	source := inputs.Source
+	destination := inputs.Destination
+	if inputs.WorkingDirectory != "" {
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(inputs.WorkingDirectory, source)
+		}
+		if !filepath.IsAbs(destination) {
+			destination = filepath.Join(inputs.WorkingDirectory, destination)
+		}
+	}
+
+	matches, err := filepath.Glob(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source %s, %s", inputs.Source, err.Error())
+	}
+
+	sources := make([]string, 0)
+	for _, match := range matches {
+		if helper.FileExists(match) {
+			sources = append(sources, match)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no files were found for source %s", inputs.Source)
+	}
+
+	isFolder := strings.HasSuffix(inputs.Destination, "/") || strings.HasSuffix(inputs.Destination, string(os.PathSeparator)) || helper.DirectoryExists(destination)
+	if len(sources) == 1 && !isFolder {
+		return []templateFile{{Source: sources[0], Destination: destination}}, nil
+	}
+
+	result := make([]templateFile, 0)
+	for _, file := range sources {
+		name := filepath.Base(file)
+		for _, extension := range templateExtensions {
+			if strings.HasSuffix(name, extension) && name != extension {
+				name = strings.TrimSuffix(name, extension)
+				break
+			}
+		}
+
+		result = append(result, templateFile{
+			Source:      file,
+			Destination: filepath.Join(destination, name),
+		})
+	}
+
+	return result, nil
+}
+
+func newTemplateData(ctx *locally_context.Context, values map[string]interface{}) TemplateData {
+	data := TemplateData{
+		Tenants:         make([]*context_entities.Tenant, 0),
+		BackendServices: make([]*service_component.BackendService, 0),
+		SpaServices:     make([]*service_component.SpaService, 0),
+		Values:          values,
+	}
+	if data.Values == nil {
+		data.Values = make(map[string]interface{})
+	}
+
+	if ctx != nil {
+		data.Context = ctx.Name
+		data.Tenants = append(data.Tenants, ctx.Tenants...)
+		data.BackendServices = append(data.BackendServices, ctx.BackendServices...)
+		data.SpaServices = append(data.SpaServices, ctx.SpaServices...)
+	}
+
+	return data
+}
+
+func renderFile(file templateFile, goTemplate bool, data TemplateData) error {
+	info, err := os.Stat(file.Source)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(file.Source)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := render(file.Source, string(content), goTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	folder := filepath.Dir(file.Destination)
+	if !helper.DirectoryExists(folder) {
+		if err := os.MkdirAll(folder, fs.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(file.Destination, []byte(rendered), info.Mode().Perm())
+}
+
+// render replaces the variables in the content and then, if enabled, executes it as a go template,
+// the variables are replaced first so ${{ }} placeholders are not read as template actions
+func render(name string, content string, goTemplate bool, data TemplateData) (string, error) {
+	content = environment.Get().ReplaceText(content)
+	if !goTemplate {
+		return content, nil
+	}
+
+	tmpl, err := template.New(filepath.Base(name)).Funcs(templateFunctions()).Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s, %s", name, err.Error())
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("error rendering template %s, %s", name, err.Error())
+	}
+
+	return buffer.String(), nil
+}
+
+// templateFunctions are the helpers available in the go templates
+func templateFunctions() template.FuncMap {
+	return template.FuncMap{
+		// var resolves a variable by name, useful when the name is built in a loop, for example
+		// {{ var (printf "config.%s.url" .Name) }}
+		"var": func(name string) string {
+			return environment.Get().Replace(fmt.Sprintf("${{ %s }}", name))
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"join": func(separator string, values []string) string {
+			return strings.Join(values, separator)
+		},
+		"json": func(value interface{}) (string, error) {
+			content, err := json.Marshal(value)
+			return string(content), err
+		},
+		"default": func(fallback interface{}, value interface{}) interface{} {
+			if value == nil {
+				return fallback
+			}
+			if v := reflect.ValueOf(value); v.IsZero() {
+				return fallback
+			}
+			return value
+		},
+	}
+}
+
+func (worker TemplatePipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*TemplateParameters, error) {
+	encoded, err := yaml.Marshal(task.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	var inputs TemplateParameters
+	err = yaml.Unmarshal(encoded, &inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inputs, nil
+}
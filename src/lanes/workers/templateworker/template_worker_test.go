@@ -0,0 +1,83 @@
+package templateworker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+	"github.com/cjlapao/locally-cli/context/service_component"
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+func TestRender(t *testing.T) {
+	env := environment.Get()
+	env.Add("global", "domain", "locally.internal")
+	env.Add("config", "acme.url", "https://acme.locally.internal")
+
+	ctx := &locally_context.Context{
+		Name:            "dev",
+		Tenants:         []*context_entities.Tenant{{Name: "acme"}, {Name: "contoso"}},
+		BackendServices: []*service_component.BackendService{{Name: "api"}},
+	}
+	data := newTemplateData(ctx, map[string]interface{}{"port": 8080})
+
+	tests := []struct {
+		name       string
+		content    string
+		goTemplate bool
+		want       string
+	}{
+		{"variables only", "server_name api.${{ global.domain }};\n{{ .Context }}", false, "server_name api.locally.internal;\n{{ .Context }}"},
+		{"tenants loop", "{{ range .Tenants }}{{ .Name }}.${{ global.domain }} {{ end }}", true, "acme.locally.internal contoso.locally.internal "},
+		{"services and values", "{{ .Context }}:{{ range .BackendServices }}{{ upper .Name }}{{ end }}:{{ .Values.port }}", true, "dev:API:8080"},
+		{"var helper", `{{ range .Tenants }}{{ var (printf "config.%s.url" .Name) }};{{ end }}`, true, "https://acme.locally.internal;${{ config.contoso.url }};"},
+		{"default helper", `{{ .Values.missing | default "none" }}`, true, "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := render(tt.name, tt.content, tt.goTemplate, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := render("invalid", "{{ range .Tenants }}", true, data); err == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}
+
+func TestResolveFiles(t *testing.T) {
+	folder := t.TempDir()
+	for _, name := range []string{"appsettings.Local.json.tmpl", ".env.tmpl", "nginx.conf"} {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(""), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := resolveFiles(&TemplateParameters{Source: "*.tmpl", Destination: "out", WorkingDirectory: folder})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Destination != filepath.Join(folder, "out", ".env") || files[1].Destination != filepath.Join(folder, "out", "appsettings.Local.json") {
+		t.Errorf("unexpected files %+v", files)
+	}
+
+	files, err = resolveFiles(&TemplateParameters{Source: filepath.Join(folder, "nginx.conf"), Destination: filepath.Join(folder, "conf.d", "api.conf")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Destination != filepath.Join(folder, "conf.d", "api.conf") {
+		t.Errorf("a single file should be rendered to the destination, got %+v", files)
+	}
+
+	if _, err := resolveFiles(&TemplateParameters{Source: "*.yaml", Destination: "out", WorkingDirectory: folder}); err == nil {
+		t.Errorf("expected an error when no files match")
+	}
+}
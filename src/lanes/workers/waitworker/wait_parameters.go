@@ -0,0 +1,191 @@
+package waitworker
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/workers/sqlworker"
+)
+
+const (
+	TargetTcp       = "tcp"
+	TargetHttp      = "http"
+	TargetSql       = "sql"
+	TargetContainer = "container"
+)
+
+const (
+	DEFAULT_TIMEOUT  = 5 * time.Minute
+	DEFAULT_INTERVAL = 2 * time.Second
+)
+
+type WaitParameters struct {
+	Tcp              string   `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	Url              string   `json:"url,omitempty" yaml:"url,omitempty"`
+	Status           []string `json:"status,omitempty" yaml:"status,omitempty"`
+	Body             string   `json:"body,omitempty" yaml:"body,omitempty"`
+	Insecure         bool     `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	ConnectionString string   `json:"connectionString,omitempty" yaml:"connectionString,omitempty"`
+	Engine           string   `json:"engine,omitempty" yaml:"engine,omitempty"`
+	Container        string   `json:"container,omitempty" yaml:"container,omitempty"`
+	Timeout          string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Interval         string   `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// Target returns what the task waits for, only one target can be set in a task
+func (c *WaitParameters) Target() string {
+	targets := c.targets()
+	if len(targets) != 1 {
+		return ""
+	}
+
+	return targets[0]
+}
+
+func (c *WaitParameters) targets() []string {
+	targets := make([]string, 0)
+	if c.Tcp != "" {
+		targets = append(targets, TargetTcp)
+	}
+	if c.Url != "" {
+		targets = append(targets, TargetHttp)
+	}
+	if c.ConnectionString != "" {
+		targets = append(targets, TargetSql)
+	}
+	if c.Container != "" {
+		targets = append(targets, TargetContainer)
+	}
+
+	return targets
+}
+
+// Validate checks the parameters before the pipeline runs, the values that still have a variable,
+// like a step output, are only known when the task runs and are checked by ValidateDecoded
+func (c *WaitParameters) Validate() error {
+	return c.validate(true)
+}
+
+// ValidateDecoded checks the decoded parameters when the task runs, all the values are checked
+func (c *WaitParameters) ValidateDecoded() error {
+	return c.validate(false)
+}
+
+func (c *WaitParameters) validate(skipVariables bool) error {
+	check := func(value string) bool {
+		return !skipVariables || !strings.Contains(value, "${{")
+	}
+
+	targets := c.targets()
+	if len(targets) == 0 {
+		return fmt.Errorf("one of tcp, url, connectionString or container is required")
+	}
+	if len(targets) > 1 {
+		return fmt.Errorf("only one of tcp, url, connectionString or container can be set, found %s", strings.Join(targets, ", "))
+	}
+
+	if c.Tcp != "" && check(c.Tcp) {
+		if _, _, err := splitHostPort(c.Tcp); err != nil {
+			return err
+		}
+	}
+	status := make([]string, 0)
+	for _, value := range c.Status {
+		if check(value) {
+			status = append(status, value)
+		}
+	}
+	if _, err := common.ParseStatusCodes(status); err != nil {
+		return err
+	}
+	if c.Body != "" && check(c.Body) {
+		if _, err := regexp.Compile(c.Body); err != nil {
+			return fmt.Errorf("invalid body expression %s, %s", c.Body, err.Error())
+		}
+	}
+	if c.ConnectionString != "" && check(c.Engine) {
+		if _, ok := sqlworker.GetDriverName(c.Engine); !ok {
+			return fmt.Errorf("invalid engine %s, it needs to be one of sqlserver, postgres, mysql or sqlite", c.Engine)
+		}
+	}
+	if check(c.Timeout) {
+		if _, err := c.GetTimeout(); err != nil {
+			return err
+		}
+	}
+	if check(c.Interval) {
+		if _, err := c.GetInterval(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *WaitParameters) Decode() {
+	env := environment.Get()
+
+	c.Tcp = strings.TrimSpace(env.Replace(c.Tcp))
+	c.Url = strings.TrimSpace(env.Replace(c.Url))
+	c.Body = env.Replace(c.Body)
+	c.ConnectionString = env.Replace(c.ConnectionString)
+	c.Engine = env.Replace(c.Engine)
+	c.Container = strings.TrimSpace(env.Replace(c.Container))
+	c.Timeout = env.Replace(c.Timeout)
+	c.Interval = env.Replace(c.Interval)
+	for i, status := range c.Status {
+		c.Status[i] = strings.TrimSpace(env.Replace(status))
+	}
+}
+
+// StatusCodes returns the accepted http status codes, a code can be an exact value like 204 or a
+// class like 2xx, by default any 2xx status is accepted
+func (c *WaitParameters) StatusCodes() ([]string, error) {
+	if len(c.Status) == 0 {
+		return []string{"2xx"}, nil
+	}
+
+	return common.ParseStatusCodes(c.Status)
+}
+
+func (c *WaitParameters) GetTimeout() (time.Duration, error) {
+	timeout, err := common.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout == 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	return timeout, nil
+}
+
+func (c *WaitParameters) GetInterval() (time.Duration, error) {
+	interval, err := common.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if interval == 0 {
+		interval = DEFAULT_INTERVAL
+	}
+
+	return interval, nil
+}
+
+func splitHostPort(address string) (string, string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return "", "", fmt.Errorf("invalid tcp address %s, it needs to be <host>:<port>", address)
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return "", "", fmt.Errorf("invalid tcp address %s, the port needs to be a number", address)
+	}
+
+	return host, port, nil
+}
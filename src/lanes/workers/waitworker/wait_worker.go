@@ -0,0 +1,330 @@
+package waitworker
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/docker"
+	"github.com/cjlapao/locally-cli/icons"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/lanes/workers/sqlworker"
+	"github.com/cjlapao/locally-cli/notifications"
+
+	"gopkg.in/yaml.v3"
+)
+
+var notify = notifications.Get()
+
+const (
+	ErrorInvalidParameters = "400"
+	ErrorTimeout           = "408"
+)
+
+// maxBodySize limits how much of a response is read when matching the body
+const maxBodySize = 1024 * 1024
+
+type WaitPipelineWorker struct {
+	name string
+}
+
+func (worker WaitPipelineWorker) New() interfaces.PipelineWorker {
+	return WaitPipelineWorker{
+		name: "wait.worker",
+	}
+}
+
+func (worker WaitPipelineWorker) Name() string {
+	return worker.name
+}
+
+func (worker WaitPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	if task.Type != pipeline_component.WaitTask {
+		notify.Debug("[%s] %s: This is not a task for me, bye...", worker.name, task.Name)
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	notify.Debug("[%s] picked up task %s to work on", worker.name, task.Name)
+
+	validationResult := worker.Validate(task)
+	if validationResult.State != entities.StateValid {
+		return validationResult
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+	if err := inputs.ValidateDecoded(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	timeout, _ := inputs.GetTimeout()
+	interval, _ := inputs.GetInterval()
+	check, err := worker.getCheck(inputs, interval)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	target := worker.describe(inputs)
+	notify.InfoWithIcon(icons.IconHourGlass, "Waiting up to %s for %s", timeout.String(), target)
+
+	attempts, elapsed, err := poll(ctx, timeout, interval, check)
+	if err != nil {
+		if ctx.Err() != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorTimeout, ctx.Err())
+		}
+		return entities.NewPipelineWorkerResultFromError(ErrorTimeout, fmt.Errorf("timed out after %s waiting for %s, %s", timeout.String(), target, err.Error()))
+	}
+
+	result.AddOutput("attempts", strconv.Itoa(attempts))
+	result.AddOutput("elapsed", elapsed.Round(time.Millisecond).String())
+
+	msg := fmt.Sprintf("%s was ready after %s for task %s", target, elapsed.Round(time.Millisecond).String(), task.Name)
+	if common.IsDebug() {
+		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+	}
+	notify.Success(msg)
+
+	result.State = entities.StateExecuted
+	return result
+}
+
+func (worker WaitPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.WaitTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+	if err := inputs.Validate(); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker WaitPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.WaitTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	timeout, _ := inputs.GetTimeout()
+	interval, _ := inputs.GetInterval()
+	lines := []string{
+		fmt.Sprintf("wait for %s", worker.describe(inputs)),
+		fmt.Sprintf("check every %s for up to %s", interval.String(), timeout.String()),
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
+// describe returns a readable description of what the task waits for, connection strings are not
+// included as they usually contain credentials
+func (worker WaitPipelineWorker) describe(inputs *WaitParameters) string {
+	switch inputs.Target() {
+	case TargetTcp:
+		return fmt.Sprintf("tcp %s", inputs.Tcp)
+	case TargetHttp:
+		codes, _ := inputs.StatusCodes()
+		description := fmt.Sprintf("%s to return %s", inputs.Url, strings.Join(codes, ", "))
+		if inputs.Body != "" {
+			description = fmt.Sprintf("%s with a body matching %s", description, inputs.Body)
+		}
+		return description
+	case TargetSql:
+		driver, _ := sqlworker.GetDriverName(inputs.Engine)
+		return fmt.Sprintf("%s database", driver)
+	case TargetContainer:
+		return fmt.Sprintf("container %s", inputs.Container)
+	default:
+		return "nothing"
+	}
+}
+
+// getCheck returns the function that checks if the target of the task is ready, each check is
+// limited to the interval so a hanging connection does not block the polling
+func (worker WaitPipelineWorker) getCheck(inputs *WaitParameters, interval time.Duration) (func(ctx context.Context) error, error) {
+	switch inputs.Target() {
+	case TargetTcp:
+		return func(ctx context.Context) error {
+			return checkTcp(ctx, inputs.Tcp, interval)
+		}, nil
+	case TargetHttp:
+		codes, err := inputs.StatusCodes()
+		if err != nil {
+			return nil, err
+		}
+		var body *regexp.Regexp
+		if inputs.Body != "" {
+			if body, err = regexp.Compile(inputs.Body); err != nil {
+				return nil, err
+			}
+		}
+		client := &http.Client{
+			Timeout: interval,
+		}
+		if inputs.Insecure {
+			client.Transport = &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			}
+		}
+		return func(ctx context.Context) error {
+			return checkHttp(ctx, client, inputs.Url, codes, body)
+		}, nil
+	case TargetSql:
+		driver, _ := sqlworker.GetDriverName(inputs.Engine)
+		return func(ctx context.Context) error {
+			return checkSql(ctx, driver, inputs.ConnectionString, interval)
+		}, nil
+	case TargetContainer:
+		return func(ctx context.Context) error {
+			return checkContainer(ctx, inputs.Container)
+		}, nil
+	default:
+		return nil, errors.New("one of tcp, url, connectionString or container is required")
+	}
+}
+
+// poll runs the check until it succeeds or the timeout expires, it returns the number of attempts
+// and how long it took, when it times out the error of the last attempt is returned
+func poll(ctx context.Context, timeout time.Duration, interval time.Duration, check func(ctx context.Context) error) (int, time.Duration, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	attempts := 0
+	for {
+		attempts += 1
+		err := check(waitCtx)
+		if err == nil {
+			return attempts, time.Since(started), nil
+		}
+		notify.Debug("attempt %s failed, %s", strconv.Itoa(attempts), err.Error())
+
+		select {
+		case <-waitCtx.Done():
+			return attempts, time.Since(started), err
+		case <-time.After(interval):
+		}
+	}
+}
+
+func checkTcp(ctx context.Context, address string, timeout time.Duration) error {
+	dialer := net.Dialer{
+		Timeout: timeout,
+	}
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return connection.Close()
+}
+
+func checkHttp(ctx context.Context, client *http.Client, url string, codes []string, body *regexp.Regexp) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !common.MatchStatusCode(response.StatusCode, codes) {
+		return fmt.Errorf("got status code %s", strconv.Itoa(response.StatusCode))
+	}
+
+	if body != nil {
+		content, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+		if err != nil {
+			return err
+		}
+		if !body.Match(content) {
+			return fmt.Errorf("the body did not match %s", body.String())
+		}
+	}
+
+	return nil
+}
+
+func checkSql(ctx context.Context, driver string, connectionString string, timeout time.Duration) error {
+	db, err := sql.Open(driver, connectionString)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return db.PingContext(pingCtx)
+}
+
+// checkContainer accepts containers that are healthy, containers without a health check are
+// accepted once they are running
+func checkContainer(ctx context.Context, container string) error {
+	status, err := docker.GetWrapper().GetContainerHealth(ctx, container)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case "healthy", "running":
+		return nil
+	default:
+		return fmt.Errorf("container %s is %s", container, status)
+	}
+}
+
+func (worker WaitPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*WaitParameters, error) {
+	encoded, err := yaml.Marshal(task.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	var inputs WaitParameters
+	err = yaml.Unmarshal(encoded, &inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inputs, nil
+}
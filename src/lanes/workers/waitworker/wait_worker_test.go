@@ -0,0 +1,147 @@
+package waitworker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/environment"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+)
+
+func runTask(inputs map[string]interface{}) entities.PipelineWorkerResult {
+	worker := WaitPipelineWorker{}.New()
+	return worker.Run(context.Background(), &pipeline_component.PipelineTask{Name: "wait", Type: pipeline_component.WaitTask, Inputs: inputs})
+}
+
+func TestWaitParameters_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  WaitParameters
+		wantErr bool
+	}{
+		{"tcp", WaitParameters{Tcp: "localhost:5432"}, false},
+		{"http with status", WaitParameters{Url: "http://localhost", Status: []string{"200", "3xx"}}, false},
+		{"sql", WaitParameters{ConnectionString: "server=localhost", Engine: "postgres"}, false},
+		{"no target", WaitParameters{}, true},
+		{"two targets", WaitParameters{Tcp: "localhost:80", Container: "api"}, true},
+		{"invalid tcp", WaitParameters{Tcp: "localhost"}, true},
+		{"invalid status", WaitParameters{Url: "http://localhost", Status: []string{"ok"}}, true},
+		{"invalid body", WaitParameters{Url: "http://localhost", Body: "("}, true},
+		{"invalid engine", WaitParameters{ConnectionString: "server=localhost", Engine: "oracle"}, true},
+		{"invalid timeout", WaitParameters{Container: "api", Timeout: "soon"}, true},
+		{"tcp from a step output", WaitParameters{Tcp: "${{ steps.deploy.outputs.address }}"}, false},
+		{"values from step outputs", WaitParameters{Url: "http://localhost", Status: []string{"${{ steps.deploy.outputs.status }}"}, Body: "${{ steps.deploy.outputs.body }}", Timeout: "${{ steps.deploy.outputs.timeout }}"}, false},
+		{"engine from a step output", WaitParameters{ConnectionString: "server=localhost", Engine: "${{ steps.deploy.outputs.engine }}"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.inputs.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitParameters_ValidateDecoded(t *testing.T) {
+	inputs := WaitParameters{Tcp: "${{ steps.deploy.outputs.address }}"}
+	if err := inputs.ValidateDecoded(); err == nil {
+		t.Errorf("expected an unresolved address to be invalid when the task runs")
+	}
+}
+
+func TestWaitPipelineWorker_StepOutput(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	worker := WaitPipelineWorker{}.New()
+	task := &pipeline_component.PipelineTask{Name: "wait", Type: pipeline_component.WaitTask, Inputs: map[string]interface{}{"tcp": "${{ steps.wait_deploy.outputs.address }}", "timeout": "5s", "interval": "100ms"}}
+	if result := worker.Validate(task); result.State != entities.StateValid {
+		t.Fatalf("expected an address from a step output to be valid before the step runs, %v", result.Error)
+	}
+	if result := worker.Run(context.Background(), task); result.State != entities.StateErrored || result.ErrorCode != ErrorInvalidParameters {
+		t.Errorf("expected an unresolved address to fail the run, got %v %v", result.State, result.Error)
+	}
+
+	environment.Get().Set("steps", "wait_deploy.outputs.address", listener.Addr().String())
+	if result := worker.Run(context.Background(), task); result.State != entities.StateExecuted {
+		t.Errorf("expected the published address to be ready, %v", result.Error)
+	}
+}
+
+func TestWaitPipelineWorker_Tcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	result := runTask(map[string]interface{}{"tcp": listener.Addr().String(), "timeout": "5s", "interval": "100ms"})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the port to be ready, %v", result.Error)
+	}
+	if result.Outputs["attempts"] != "1" {
+		t.Errorf("expected one attempt, got %s", result.Outputs["attempts"])
+	}
+
+	address := listener.Addr().String()
+	listener.Close()
+	result = runTask(map[string]interface{}{"tcp": address, "timeout": "300ms", "interval": "100ms"})
+	if result.State != entities.StateErrored || result.ErrorCode != ErrorTimeout {
+		t.Errorf("expected a timeout, got %v", result.State)
+	}
+}
+
+func TestWaitPipelineWorker_Http(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"Healthy"}`))
+	}))
+	defer server.Close()
+
+	result := runTask(map[string]interface{}{"url": server.URL, "body": `"status":\s*"Healthy"`, "timeout": "5s", "interval": "50ms"})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the endpoint to be ready, %v", result.Error)
+	}
+	if result.Outputs["attempts"] != "3" {
+		t.Errorf("expected three attempts, got %s", result.Outputs["attempts"])
+	}
+
+	result = runTask(map[string]interface{}{"url": server.URL, "status": []interface{}{"204"}, "timeout": "200ms", "interval": "50ms"})
+	if result.State != entities.StateErrored {
+		t.Errorf("expected a timeout for an unexpected status code")
+	}
+}
+
+func TestWaitPipelineWorker_Sql(t *testing.T) {
+	database := filepath.Join(t.TempDir(), "test.db")
+
+	result := runTask(map[string]interface{}{"engine": "sqlite", "connectionString": database, "timeout": "5s"})
+	if result.State != entities.StateExecuted {
+		t.Fatalf("expected the database to be ready, %v", result.Error)
+	}
+}
+
+func TestWaitPipelineWorker_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	worker := WaitPipelineWorker{}.New()
+	result := worker.Run(ctx, &pipeline_component.PipelineTask{Name: "wait", Type: pipeline_component.WaitTask, Inputs: map[string]interface{}{"tcp": "127.0.0.1:1", "timeout": "1m"}})
+	if result.State != entities.StateErrored || result.Error != context.Canceled {
+		t.Errorf("expected the wait to be cancelled, got %v", result.Error)
+	}
+}
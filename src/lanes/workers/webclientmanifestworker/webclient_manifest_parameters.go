@@ -0,0 +1,80 @@
+package webclientmanifestworker
+
+import (
+	"strings"
+
+	"github.com/cjlapao/locally-cli/environment"
+)
+
+const (
+	FormatJson = "json"
+	FormatJs   = "js"
+)
+
+const (
+	DEFAULT_MANIFEST_NAME     = "environment.json"
+	DEFAULT_MANIFEST_VARIABLE = "window.__env"
+)
+
+type WebClientManifestParameters struct {
+	SpaService  string                 `json:"spaService,omitempty" yaml:"spaService,omitempty"`
+	Path        string                 `json:"path,omitempty" yaml:"path,omitempty"`
+	Format      string                 `json:"format,omitempty" yaml:"format,omitempty"`
+	Variable    string                 `json:"variable,omitempty" yaml:"variable,omitempty"`
+	Merge       bool                   `json:"merge,omitempty" yaml:"merge,omitempty"`
+	Environment map[string]interface{} `json:"environment,omitempty" yaml:"environment,omitempty"`
+}
+
+func (c *WebClientManifestParameters) Validate() bool {
+	if c.SpaService == "" && c.Path == "" {
+		return false
+	}
+	if c.Format != "" && !strings.EqualFold(c.Format, FormatJson) && !strings.EqualFold(c.Format, FormatJs) {
+		return false
+	}
+
+	return true
+}
+
+func (c *WebClientManifestParameters) Decode() {
+	env := environment.Get()
+
+	c.SpaService = env.Replace(c.SpaService)
+	c.Path = env.Replace(c.Path)
+	c.Format = strings.ToLower(env.Replace(c.Format))
+	if c.Format == "" {
+		c.Format = FormatJson
+	}
+	c.Variable = env.Replace(c.Variable)
+	if c.Variable == "" {
+		c.Variable = DEFAULT_MANIFEST_VARIABLE
+	}
+	for key, value := range c.Environment {
+		c.Environment[key] = decodeValue(value)
+	}
+}
+
+// decodeValue replaces the variables in a value, maps and lists are copied so the values of the
+// configuration are not changed
+func decodeValue(value interface{}) interface{} {
+	env := environment.Get()
+
+	switch v := value.(type) {
+	case string:
+		return env.Replace(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, item := range v {
+			result[key] = decodeValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = decodeValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
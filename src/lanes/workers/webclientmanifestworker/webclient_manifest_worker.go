@@ -0,0 +1,242 @@
+package webclientmanifestworker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/context/pipeline_component"
+	"github.com/cjlapao/locally-cli/context/service_component"
+	"github.com/cjlapao/locally-cli/lanes/entities"
+	"github.com/cjlapao/locally-cli/lanes/interfaces"
+	"github.com/cjlapao/locally-cli/notifications"
+
+	"github.com/cjlapao/common-go/helper"
+	"gopkg.in/yaml.v3"
+)
+
+var notify = notifications.Get()
+
+const (
+	ErrorInvalidParameters = "400"
+	ErrorNotFound          = "404"
+	ErrorExecuting         = "500"
+)
+
+type WebClientManifestPipelineWorker struct {
+	name string
+}
+
+func (worker WebClientManifestPipelineWorker) New() interfaces.PipelineWorker {
+	return WebClientManifestPipelineWorker{
+		name: "webclientmanifest.worker",
+	}
+}
+
+func (worker WebClientManifestPipelineWorker) Name() string {
+	return worker.name
+}
+
+func (worker WebClientManifestPipelineWorker) Run(ctx context.Context, task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+
+	if task.Type != pipeline_component.WebClientManifestTask {
+		notify.Debug("[%s] %s: This is not a task for me, bye...", worker.name, task.Name)
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	notify.Debug("[%s] picked up task %s to work on", worker.name, task.Name)
+
+	validationResult := worker.Validate(task)
+	if validationResult.State != entities.StateValid {
+		return validationResult
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	path, values, err := worker.buildManifest(inputs)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	content, err := renderManifest(inputs, values)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	folder := filepath.Dir(path)
+	if !helper.DirectoryExists(folder) {
+		if err := os.MkdirAll(folder, fs.ModePerm); err != nil {
+			return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+		}
+	}
+
+	if err := helper.WriteToFile(content, path); err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorExecuting, err)
+	}
+
+	msg := fmt.Sprintf("Web client manifest %s written successfully for task %s", path, task.Name)
+	if common.IsDebug() {
+		msg = fmt.Sprintf("[%s] %s", worker.name, msg)
+	}
+	notify.Success(msg)
+
+	result.Output = content
+	result.AddOutput("path", path)
+	result.State = entities.StateExecuted
+	return result
+}
+
+func (worker WebClientManifestPipelineWorker) Validate(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.WebClientManifestTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	if !inputs.Validate() {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, errors.New("a spaService or a path is required and the format needs to be json or js"))
+	}
+
+	result.State = entities.StateValid
+	return result
+}
+
+func (worker WebClientManifestPipelineWorker) Plan(task *pipeline_component.PipelineTask) entities.PipelineWorkerResult {
+	result := entities.PipelineWorkerResult{}
+	if task.Type != pipeline_component.WebClientManifestTask {
+		result.State = entities.StateIgnored
+		return result
+	}
+
+	inputs, err := worker.parseParameters(task)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorInvalidParameters, err)
+	}
+
+	inputs.Decode()
+
+	path, values, err := worker.buildManifest(inputs)
+	if err != nil {
+		return entities.NewPipelineWorkerResultFromError(ErrorNotFound, err)
+	}
+
+	keys := make([]string, 0)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{fmt.Sprintf("write %s manifest %s", inputs.Format, path)}
+	if len(keys) > 0 {
+		lines = append(lines, fmt.Sprintf("with %s", strings.Join(keys, ", ")))
+	}
+
+	result.Output = strings.Join(lines, "\n")
+	result.State = entities.StateValid
+	return result
+}
+
+// buildManifest returns the path of the manifest and its values, the environment of the spa
+// service is used as base and the values of the task are added on top of it
+func (worker WebClientManifestPipelineWorker) buildManifest(inputs *WebClientManifestParameters) (string, map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	path := inputs.Path
+
+	if inputs.SpaService != "" {
+		spaService := getSpaService(inputs.SpaService)
+		if spaService == nil {
+			return "", nil, fmt.Errorf("spa service %s was not found in the current context", inputs.SpaService)
+		}
+
+		if path == "" {
+			path = DEFAULT_MANIFEST_NAME
+		}
+		if !filepath.IsAbs(path) {
+			path = helper.JoinPath(spaService.Path, path)
+		}
+
+		for key, value := range spaService.Environment {
+			values[key] = decodeValue(value)
+		}
+	}
+
+	if inputs.Merge && inputs.Format == FormatJson && helper.FileExists(path) {
+		content, err := helper.ReadFromFile(path)
+		if err != nil {
+			return "", nil, err
+		}
+
+		existing := make(map[string]interface{})
+		if err := json.Unmarshal(content, &existing); err != nil {
+			return "", nil, fmt.Errorf("could not merge with %s, %s", path, err.Error())
+		}
+		for key, value := range values {
+			existing[key] = value
+		}
+		values = existing
+	}
+
+	for key, value := range inputs.Environment {
+		values[key] = value
+	}
+
+	return path, values, nil
+}
+
+func renderManifest(inputs *WebClientManifestParameters, values map[string]interface{}) (string, error) {
+	content, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if inputs.Format == FormatJs {
+		return fmt.Sprintf("%s = %s;\n", inputs.Variable, string(content)), nil
+	}
+
+	return string(content), nil
+}
+
+func getSpaService(name string) *service_component.SpaService {
+	config := configuration.Get()
+	for _, spaService := range config.GetCurrentContext().SpaServices {
+		if strings.EqualFold(spaService.Name, name) {
+			return spaService
+		}
+	}
+
+	return nil
+}
+
+func (worker WebClientManifestPipelineWorker) parseParameters(task *pipeline_component.PipelineTask) (*WebClientManifestParameters, error) {
+	encoded, err := yaml.Marshal(task.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	var inputs WebClientManifestParameters
+	err = yaml.Unmarshal(encoded, &inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inputs, nil
+}
@@ -0,0 +1,45 @@
+package webclientmanifestworker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildManifest_Merge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "environment.json")
+	if err := os.WriteFile(path, []byte(`{"apiUrl":"http://localhost:5000","featureFlags":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := &WebClientManifestParameters{
+		Path:        path,
+		Merge:       true,
+		Environment: map[string]interface{}{"apiUrl": "https://api.locally.internal"},
+	}
+	inputs.Decode()
+
+	_, values, err := WebClientManifestPipelineWorker{}.buildManifest(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["apiUrl"] != "https://api.locally.internal" || values["featureFlags"] != true {
+		t.Errorf("unexpected manifest values %v", values)
+	}
+}
+
+func TestRenderManifest_Js(t *testing.T) {
+	inputs := &WebClientManifestParameters{Path: "env.js", Format: "js"}
+	inputs.Decode()
+
+	content, err := renderManifest(inputs, map[string]interface{}{"apiUrl": "https://api.locally.internal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(content, "window.__env = {") || !strings.HasSuffix(content, "};\n") {
+		t.Errorf("unexpected js manifest %s", content)
+	}
+}
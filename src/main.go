@@ -70,6 +70,8 @@ func main() {
 		tester.TestOperations(subCommand)
 	case "keyvault":
 		operations.AzureKeyvaultOperations(subCommand)
+	case "secrets":
+		operations.SecretsOperations(subCommand)
 	case "nuget":
 		operations.NugetOperations(subCommand)
 	case "config":
@@ -1,6 +1,9 @@
 package notifications
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type Notification struct {
 	Level     NotificationLevel
@@ -11,5 +14,6 @@ type Notification struct {
 }
 
 type Notifications struct {
+	mutex sync.Mutex
 	Items []Notification
 }
@@ -32,8 +32,11 @@ func Get() *NotificationsService {
 }
 
 func (svc *NotificationsService) Reset() {
-	for _, n := range svc.notifications.Items {
-		n.State = ReadState
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
+	for i := range svc.notifications.Items {
+		svc.notifications.Items[i].State = ReadState
 	}
 }
 
@@ -110,6 +113,7 @@ func (svc *NotificationsService) Notify(print bool, level NotificationLevel, mes
 		message = fmt.Sprintf(message, words...)
 	}
 
+	svc.notifications.mutex.Lock()
 	found := false
 	for _, n := range svc.notifications.Items {
 		if strings.EqualFold(level.String(), n.Level.String()) && strings.EqualFold(message, n.Message) && strings.EqualFold(svc.Service, n.Service) {
@@ -126,6 +130,7 @@ func (svc *NotificationsService) Notify(print bool, level NotificationLevel, mes
 			Service:   svc.Service,
 		})
 	}
+	svc.notifications.mutex.Unlock()
 
 	if print {
 		switch level {
@@ -182,6 +187,9 @@ func (svc *NotificationsService) Notify(print bool, level NotificationLevel, mes
 }
 
 func (svc *NotificationsService) HasErrors() bool {
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
 	for _, n := range svc.notifications.Items {
 		if n.Level == CriticalLevel || n.Level == ErrorLevel && n.State == NewState {
 			return true
@@ -191,6 +199,9 @@ func (svc *NotificationsService) HasErrors() bool {
 }
 
 func (svc *NotificationsService) CountErrors() uint {
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
 	count := 0
 	for _, n := range svc.notifications.Items {
 		if n.Level == CriticalLevel || n.Level == ErrorLevel && n.State == NewState {
@@ -201,6 +212,9 @@ func (svc *NotificationsService) CountErrors() uint {
 }
 
 func (svc *NotificationsService) HasWarning() bool {
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
 	for _, n := range svc.notifications.Items {
 		if n.Level == WarningLevel && n.State == NewState {
 			return true
@@ -210,6 +224,9 @@ func (svc *NotificationsService) HasWarning() bool {
 }
 
 func (svc *NotificationsService) CountWarnings() uint {
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
 	count := 0
 	for _, n := range svc.notifications.Items {
 		if n.Level == WarningLevel && n.State == NewState {
@@ -220,6 +237,9 @@ func (svc *NotificationsService) CountWarnings() uint {
 }
 
 func (svc *NotificationsService) HasCritical() bool {
+	svc.notifications.mutex.Lock()
+	defer svc.notifications.mutex.Unlock()
+
 	for _, n := range svc.notifications.Items {
 		if n.Level == CriticalLevel && n.State == NewState {
 			return true
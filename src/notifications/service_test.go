@@ -0,0 +1,16 @@
+package notifications
+
+import "testing"
+
+func TestNotificationsService_Reset(t *testing.T) {
+	svc := New("test")
+	svc.AddError("something failed")
+	if !svc.HasErrors() {
+		t.Fatalf("expected the error to be reported")
+	}
+
+	svc.Reset()
+	if svc.HasErrors() {
+		t.Errorf("expected the errors to be marked as read after a reset")
+	}
+}
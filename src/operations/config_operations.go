@@ -50,7 +50,9 @@ func ConfigOperations(subCommand string) {
 		}
 		systemService.CheckFolders(true)
 		caddy := caddy.Get()
-		caddy.GenerateDockerFiles()
+		if err := caddy.GenerateDockerFiles(); err != nil {
+			notify.FromError(err, "There was an error generating docker files")
+		}
 		if err := caddy.GenerateCaddyFiles(); err != nil {
 			notify.FromError(err, "There was an error generating caddy files")
 		}
@@ -317,6 +317,7 @@ func DockerOperations(subCommand string, options *docker.DockerServiceOptions) {
 			options = &docker.DockerServiceOptions{
 				Name:              service,
 				BuildDependencies: buildDependencies,
+				Strict:            !helper.GetFlagSwitch("no-strict", false),
 			}
 		}
 
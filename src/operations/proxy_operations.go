@@ -24,7 +24,9 @@ func ProxyOperations(subCommand string) {
 			os.Exit(0)
 		}
 		systemSvc.CheckFolders(true)
-		caddySvc.GenerateDockerFiles()
+		if err := caddySvc.GenerateDockerFiles(); err != nil {
+			notify.FromError(err, "There was an error generating docker files")
+		}
 		if err := caddySvc.GenerateCaddyFiles(); err != nil {
 			notify.FromError(err, "There was an error generating caddy files")
 		}
@@ -34,7 +36,9 @@ func ProxyOperations(subCommand string) {
 			os.Exit(0)
 		}
 		systemSvc.CheckFolders(false)
-		caddySvc.GenerateDockerFiles()
+		if err := caddySvc.GenerateDockerFiles(); err != nil {
+			notify.FromError(err, "There was an error generating docker files")
+		}
 		if err := caddySvc.BuildContainer(); err != nil {
 			notify.FromError(err, "There was an error building locally container")
 		}
@@ -44,7 +48,9 @@ func ProxyOperations(subCommand string) {
 			os.Exit(0)
 		}
 		systemSvc.CheckFolders(true)
-		caddySvc.GenerateDockerFiles()
+		if err := caddySvc.GenerateDockerFiles(); err != nil {
+			notify.FromError(err, "There was an error generating docker files")
+		}
 		if err := caddySvc.RebuildContainer(); err != nil {
 			notify.FromError(err, "There was an error rebuilding locally container")
 		}
@@ -55,7 +61,9 @@ func ProxyOperations(subCommand string) {
 		}
 		if helper.GetFlagSwitch("generate", false) {
 			systemSvc.CheckFolders(true)
-			caddySvc.GenerateDockerFiles()
+			if err := caddySvc.GenerateDockerFiles(); err != nil {
+				notify.FromError(err, "There was an error generating docker files")
+			}
 			if err := caddySvc.GenerateCaddyFiles(); err != nil {
 				notify.FromError(err, "There was an error generating caddy files")
 			}
@@ -71,7 +79,9 @@ func ProxyOperations(subCommand string) {
 		}
 		if helper.GetFlagSwitch("generate", false) {
 			systemSvc.CheckFolders(true)
-			caddySvc.GenerateDockerFiles()
+			if err := caddySvc.GenerateDockerFiles(); err != nil {
+				notify.FromError(err, "There was an error generating docker files")
+			}
 			if err := caddySvc.GenerateCaddyFiles(); err != nil {
 				notify.FromError(err, "There was an error generating caddy files")
 			}
@@ -0,0 +1,144 @@
+package operations
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/help"
+	"github.com/cjlapao/locally-cli/icons"
+	"github.com/cjlapao/locally-cli/vaults/secrets_vault"
+
+	"github.com/cjlapao/common-go/helper"
+)
+
+func SecretsOperations(subCommand string) {
+	if subCommand == "" && helper.GetFlagSwitch("help", false) {
+		help.ShowHelpForSecretsCommand()
+		os.Exit(0)
+	}
+
+	name := common.VerifyCommand(helper.GetArgumentAt(2))
+
+	switch subCommand {
+	case "set":
+		if name == "" || helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForSecretsSetCommand()
+			os.Exit(0)
+		}
+
+		// reading the value from the input keeps it out of the shell history
+		value := common.VerifyCommand(helper.GetArgumentAt(3))
+		if value == "" || value == "-" {
+			content, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				notify.FromError(err, "Error reading the secret value")
+				return
+			}
+			value = strings.TrimRight(string(content), "\r\n")
+		}
+
+		store := getSecretsStore()
+		if store == nil {
+			return
+		}
+		if err := store.Set(name, value); err != nil {
+			notify.Error("Error setting the secret %s, %s", name, err.Error())
+			return
+		}
+
+		notify.Success("Secret %s was set", strings.ToLower(name))
+	case "get":
+		if name == "" || helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForSecretsGetCommand()
+			os.Exit(0)
+		}
+
+		store := getSecretsStore()
+		if store == nil {
+			return
+		}
+		value, err := store.Get(name)
+		if err != nil {
+			notify.Error("Error getting the secret %s, %s", name, err.Error())
+			return
+		}
+
+		// only the value is printed so it can be piped to other commands
+		fmt.Println(value)
+	case "list":
+		if helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForSecretsListCommand()
+			os.Exit(0)
+		}
+
+		store := getSecretsStore()
+		if store == nil {
+			return
+		}
+		names, err := store.List()
+		if err != nil {
+			notify.Error("Error listing the secrets, %s", err.Error())
+			return
+		}
+
+		if len(names) == 0 {
+			notify.Info("There are no secrets in %s", store.FilePath())
+			return
+		}
+		for _, secret := range names {
+			notify.InfoWithIcon(icons.IconKey, "secrets.%s", secret)
+		}
+	case "rm":
+		if name == "" || helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForSecretsRemoveCommand()
+			os.Exit(0)
+		}
+
+		store := getSecretsStore()
+		if store == nil {
+			return
+		}
+		if err := store.Remove(name); err != nil {
+			notify.Error("Error removing the secret %s, %s", name, err.Error())
+			return
+		}
+
+		notify.Success("Secret %s was removed", strings.ToLower(name))
+	case "rotate":
+		if helper.GetFlagSwitch("help", false) {
+			help.ShowHelpForSecretsRotateCommand()
+			os.Exit(0)
+		}
+
+		store := getSecretsStore()
+		if store == nil {
+			return
+		}
+		if !store.Exists() {
+			notify.Warning("There are no secrets to rotate in %s", store.FilePath())
+			return
+		}
+		if err := store.Rotate(os.Getenv(secrets_vault.NEW_PASSPHRASE_ENV_VARIABLE)); err != nil {
+			notify.Error("Error rotating the secrets key, %s", err.Error())
+			return
+		}
+
+		notify.Success("Secrets key was rotated")
+	default:
+		help.ShowHelpForSecretsCommand()
+		os.Exit(0)
+	}
+}
+
+func getSecretsStore() *secrets_vault.SecretsStore {
+	store, err := secrets_vault.GetStore()
+	if err != nil {
+		notify.Error("Error opening the secrets, %s", err.Error())
+		return nil
+	}
+
+	return store
+}
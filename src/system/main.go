@@ -11,11 +11,14 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/cjlapao/common-go/execution_context"
 	"github.com/cjlapao/common-go/helper"
 )
 
+const SHUTDOWN_TIMEOUT = 30 * time.Second
+
 type SystemService struct {
 	BuildErrors   []error
 	BuildWarnings []string
@@ -154,14 +157,25 @@ func (svc *SystemService) setDefaultEnvVariables() {
 }
 
 func (svc *SystemService) setupGracefulShutdown() {
-	sigChan := make(chan os.Signal)
+	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		sig := <-sigChan
-		if sig == os.Interrupt || sig == os.Kill {
+		if sig == os.Interrupt || sig == syscall.SIGTERM {
 			caddySvc := caddy.GetWrapper()
 			go caddySvc.Stop()
+
+			// a second signal does not wait for the running tasks
+			go func() {
+				<-sigChan
+				os.Exit(2)
+			}()
+
+			notify.Warning("Stopping, waiting for the running tasks to be cancelled")
+			if !common.Shutdown(SHUTDOWN_TIMEOUT) {
+				notify.Warning("Running tasks did not stop in %s, exiting", SHUTDOWN_TIMEOUT.String())
+			}
 			os.Exit(2)
 		}
 	}()
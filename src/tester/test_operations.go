@@ -37,15 +37,15 @@ func TestOperations(subCommand string) {
 		pipeline := common.VerifyCommand(helper.GetArgumentAt(3))
 		switch action {
 		case "run":
-			if err := automationService.Validate(pipeline); err == nil {
-				if err := automationService.Run(pipeline); err != nil {
+			if err := automationService.Validate(pipeline, nil); err == nil {
+				if err := automationService.Run(pipeline, nil); err != nil {
 					notify.Error("There was an error executing the requested pipeline %s", pipeline)
 				}
 			} else {
 				notify.Error("There was an error validating the requested pipeline %s", pipeline)
 			}
 		case "validate":
-			if err := automationService.Validate(pipeline); err != nil {
+			if err := automationService.Validate(pipeline, nil); err != nil {
 				notify.Error("There was an error validating the requested pipeline %s", pipeline)
 			}
 		}
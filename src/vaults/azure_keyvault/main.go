@@ -5,14 +5,18 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/entities"
 	"github.com/cjlapao/locally-cli/environment"
 	"github.com/cjlapao/locally-cli/notifications"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
 )
@@ -28,8 +32,19 @@ type AzureKeyVault struct {
 type AzureKeyVaultOptions struct {
 	KeyVaultUri  string
 	DecodeBase64 bool
+	// Prefix only syncs the secrets which name starts with it
+	Prefix string
+	// Secrets only syncs these secrets, they are read directly without listing the vault
+	Secrets []string
+	// Credential is used instead of the default azure credential of the context
+	Credential azcore.TokenCredential
+	// ClientOptions changes how the keyvault api is called
+	ClientOptions *azsecrets.ClientOptions
 }
 
+// SYNC_CONCURRENCY is how many secrets are read from the keyvault at the same time
+const SYNC_CONCURRENCY = 8
+
 func New(id string, options *AzureKeyVaultOptions) *AzureKeyVault {
 	result := AzureKeyVault{
 		name:      "keyvault",
@@ -45,7 +60,6 @@ func (c AzureKeyVault) Name() string {
 }
 
 func (c AzureKeyVault) Sync() (map[string]interface{}, error) {
-	env := environment.Get()
 	config := configuration.Get()
 	configContext := config.GetCurrentContext()
 	result := make(map[string]interface{})
@@ -62,90 +76,247 @@ func (c AzureKeyVault) Sync() (map[string]interface{}, error) {
 
 	defer cancel()
 
-	c.setAuthorization()
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	client, err := c.getClient()
 	if err != nil {
-		notify.FromError(err, "failed to obtain a credential: %v")
+		notify.Error("There was an error getting the keyvault client, the vault %s will not be sync", c.name)
 		return nil, err
 	}
 
-	client, err := azsecrets.NewClient(c.options.KeyVaultUri, cred, nil)
+	names, err := c.getSecretNames(context, client)
 	if err != nil {
-		notify.Error("There was an error getting the keyvault client, the vault %s will not be sync", c.name)
 		return nil, err
 	}
 
+	notify.Debug("Syncing %v secrets from the keyvault %s", len(names), c.options.KeyVaultUri)
+	values, err := c.getSecretValues(context, client, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		if c.options.DecodeBase64 {
+			b, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				notify.Warning("Secret %s is not a valid base64 encoded, will sync the raw value", name)
+			} else {
+				value = string(b)
+			}
+		}
+
+		if value != "" {
+			formattedKey := c.store(configContext.EnvironmentVariables, name, value)
+			result[formattedKey] = value
+		} else {
+			notify.Debug("Secret %s is empty, will not sync", name)
+		}
+	}
+
+	notify.Debug("Saving the sync of the keyvault %s to the keyvault %s environment variables", c.options.KeyVaultUri, c.partition)
+	configContext.SaveEnvironmentVariables()
+	return result, nil
+}
+
+// SetSecret creates or updates a secret in the keyvault and adds it to the keyvault environment
+// variables, the value is base64 encoded if the vault decodes its values
+func (c AzureKeyVault) SetSecret(name, value string) error {
+	config := configuration.Get()
+	configContext := config.GetCurrentContext()
+
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	defer cancel()
+
+	secretValue := value
+	if c.options.DecodeBase64 {
+		secretValue = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	if _, err := client.SetSecret(ctx, name, azsecrets.SetSecretParameters{Value: &secretValue}, nil); err != nil {
+		return fmt.Errorf("there was an error setting secret %s in the keyvault, %s", name, err.Error())
+	}
+
+	if configContext != nil {
+		c.store(configContext.EnvironmentVariables, name, value)
+		configContext.SaveEnvironmentVariables()
+	}
+
+	return nil
+}
+
+// DeleteSecret deletes a secret from the keyvault and from the keyvault environment variables,
+// secrets that do not exist are ignored
+func (c AzureKeyVault) DeleteSecret(name string) error {
+	env := environment.Get()
+	config := configuration.Get()
+	configContext := config.GetCurrentContext()
+
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	defer cancel()
+
+	if _, err := client.DeleteSecret(ctx, name, nil); err != nil {
+		var responseError *azcore.ResponseError
+		if !errors.As(err, &responseError) || responseError.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("there was an error deleting secret %s from the keyvault, %s", name, err.Error())
+		}
+		notify.Warning("Secret %s does not exist in the keyvault, nothing to delete", name)
+	}
+
+	formattedKey := c.formatKey(name)
+	if env.Get(c.name, formattedKey) != nil {
+		env.Remove(c.name, formattedKey)
+	}
+	if configContext != nil && configContext.EnvironmentVariables != nil && configContext.EnvironmentVariables.KeyVault != nil {
+		delete(configContext.EnvironmentVariables.KeyVault, formattedKey)
+		configContext.SaveEnvironmentVariables()
+	}
+
+	return nil
+}
+
+func (c AzureKeyVault) getClient() (*azsecrets.Client, error) {
+	credential := c.options.Credential
+	if credential == nil {
+		c.setAuthorization()
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			notify.FromError(err, "failed to obtain a credential: %v")
+			return nil, err
+		}
+		credential = cred
+	}
+
+	return azsecrets.NewClient(c.options.KeyVaultUri, credential, c.options.ClientOptions)
+}
+
+// getSecretNames returns the secrets to sync, the keyvault is only listed if there is no list of
+// secrets to sync
+func (c AzureKeyVault) getSecretNames(ctx context.Context, client *azsecrets.Client) ([]string, error) {
+	if len(c.options.Secrets) > 0 {
+		return c.options.Secrets, nil
+	}
+
+	names := make([]string, 0)
 	pager := client.NewListSecretsPager(nil)
 	for pager.More() {
-		page, err := pager.NextPage(context)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			notify.Error("There was an error getting a page from the keyvault, the vault %s was only partially sync", c.name)
 			return nil, err
 		}
 
 		for _, secret := range page.Value {
-			value := ""
-			secretResp, err := client.GetSecret(context, secret.ID.Name(), secret.ID.Version(), nil)
-			if err != nil {
-				notify.Error("There was an error getting secret %s from the keyvault, it will not sync", secret.ID.Name())
-				return nil, err
+			if secret.ID == nil {
+				continue
 			}
-			if secretResp.Value == nil {
-				notify.Warning("Secret %s is empty, will not sync", secretResp.ID.Name())
+			name := secret.ID.Name()
+			if c.options.Prefix != "" && !strings.HasPrefix(strings.ToLower(name), strings.ToLower(c.options.Prefix)) {
+				continue
 			}
+			names = append(names, name)
+		}
 
-			if c.options.DecodeBase64 {
-				b, err := base64.StdEncoding.DecodeString(*secretResp.Value)
-				if err != nil {
-					notify.Warning("Secret %s is not a valid base64 encoded, will sync the raw value", secretResp.ID.Name())
-					value = *secretResp.Value
-				} else {
-					value = string(b)
-				}
-			} else {
-				value = string(*secretResp.Value)
-			}
+		if page.NextLink == nil {
+			break
+		}
+	}
 
-			if value != "" {
-				formattedKey := strings.ToLower(secret.ID.Name())
-				if c.partition != "" {
-					formattedKey = fmt.Sprintf("%s.%s", strings.ToLower(c.partition), strings.ToLower(secret.ID.Name()))
-				}
-				result[formattedKey] = value
+	return names, nil
+}
 
-				if configContext.EnvironmentVariables != nil {
-					if configContext.EnvironmentVariables.KeyVault == nil {
-						configContext.EnvironmentVariables.KeyVault = make(map[string]interface{})
+// getSecretValues reads the secrets in parallel, secrets without a value are not returned
+func (c AzureKeyVault) getSecretValues(ctx context.Context, client *azsecrets.Client, names []string) (map[string]string, error) {
+	values := make(map[string]string)
+	var mutex sync.Mutex
+	var firstErr error
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < SYNC_CONCURRENCY; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				secretResp, err := client.GetSecret(ctx, name, "", nil)
+				mutex.Lock()
+				switch {
+				case err != nil:
+					if firstErr == nil {
+						notify.Error("There was an error getting secret %s from the keyvault, it will not sync", name)
+						firstErr = err
 					}
+				case secretResp.Value == nil:
+					notify.Warning("Secret %s is empty, will not sync", name)
+				default:
+					values[name] = *secretResp.Value
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
 
-					notify.Debug("Adding %s to the %s keyvault environment variable", formattedKey, c.partition)
-					configContext.EnvironmentVariables.KeyVault[formattedKey] = value
+	for _, name := range names {
+		queue <- name
+	}
+	close(queue)
+	wg.Wait()
 
-					// forcing adding to existing environment vault
-					env.Add(c.name, formattedKey, value)
-				}
+	if firstErr != nil {
+		return nil, firstErr
+	}
 
-				notify.Debug("%s: %s", fmt.Sprintf("%s.%s", c.partition, secret.ID.Name()), value)
+	return values, nil
+}
 
-			} else {
-				notify.Debug("Secret %s is empty, will not sync", secretResp.ID.Name())
-			}
-		}
+// store adds the secret to the keyvault environment variables and returns its key
+func (c AzureKeyVault) store(variables *entities.EnvironmentVariables, name, value string) string {
+	env := environment.Get()
+	formattedKey := c.formatKey(name)
 
-		if page.NextLink == nil {
-			break
+	if variables != nil {
+		if variables.KeyVault == nil {
+			variables.KeyVault = make(map[string]interface{})
 		}
+
+		notify.Debug("Adding %s to the %s keyvault environment variable", formattedKey, c.partition)
+		variables.KeyVault[formattedKey] = value
+
+		// forcing adding to existing environment vault
+		env.Add(c.name, formattedKey, value)
 	}
 
-	notify.Debug("Saving the sync of the keyvault %s to the keyvault %s environment variables", c.options.KeyVaultUri, c.partition)
-	configContext.SaveEnvironmentVariables()
-	return result, nil
+	return formattedKey
+}
+
+func (c AzureKeyVault) formatKey(name string) string {
+	if c.partition != "" {
+		return fmt.Sprintf("%s.%s", strings.ToLower(c.partition), strings.ToLower(name))
+	}
+
+	return strings.ToLower(name)
 }
 
 func (c AzureKeyVault) setAuthorization() error {
 	config := configuration.Get()
 	context := config.GetCurrentContext()
 
+	if context == nil {
+		return errors.New("cannot find a context for authorization")
+	}
+
 	if context.Infrastructure == nil {
 		return errors.New("cannot find infrastructure for authorization")
 	}
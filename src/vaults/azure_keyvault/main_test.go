@@ -0,0 +1,223 @@
+package azure_keyvault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	"github.com/cjlapao/locally-cli/entities"
+	"github.com/cjlapao/locally-cli/environment"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+)
+
+type fakeCredential struct{}
+
+func (c fakeCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// fakeKeyVault is a local stand-in for the secrets api of a keyvault
+type fakeKeyVault struct {
+	mutex   sync.Mutex
+	server  *httptest.Server
+	secrets map[string]string
+	gets    []string
+}
+
+func newFakeKeyVault(t *testing.T, secrets map[string]string) *fakeKeyVault {
+	vault := &fakeKeyVault{secrets: secrets}
+	vault.server = httptest.NewTLSServer(http.HandlerFunc(vault.handle))
+	t.Cleanup(vault.server.Close)
+
+	return vault
+}
+
+func (vault *fakeKeyVault) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.Header().Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant" resource="https://vault.azure.net"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	vault.mutex.Lock()
+	defer vault.mutex.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+	if len(parts) == 1 && r.Method == http.MethodGet {
+		names := make([]string, 0)
+		for name := range vault.secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		items := make([]map[string]string, 0)
+		for _, name := range names {
+			items = append(items, map[string]string{"id": fmt.Sprintf("%s/secrets/%s", vault.server.URL, name)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": items})
+		return
+	}
+
+	name := parts[1]
+	switch r.Method {
+	case http.MethodGet:
+		vault.gets = append(vault.gets, name)
+		value, ok := vault.secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": "SecretNotFound", "message": "not found"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": fmt.Sprintf("%s/secrets/%s/1", vault.server.URL, name), "value": value})
+	case http.MethodPut:
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		vault.secrets[name] = body["value"]
+		json.NewEncoder(w).Encode(map[string]string{"id": fmt.Sprintf("%s/secrets/%s/2", vault.server.URL, name), "value": body["value"]})
+	case http.MethodDelete:
+		if _, ok := vault.secrets[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": "SecretNotFound", "message": "not found"}})
+			return
+		}
+		delete(vault.secrets, name)
+		json.NewEncoder(w).Encode(map[string]string{"id": fmt.Sprintf("%s/secrets/%s", vault.server.URL, name)})
+	}
+}
+
+func (vault *fakeKeyVault) options() *AzureKeyVaultOptions {
+	return &AzureKeyVaultOptions{
+		KeyVaultUri: vault.server.URL,
+		Credential:  fakeCredential{},
+		ClientOptions: &azsecrets.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				Transport: vault.server.Client(),
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+			},
+			DisableChallengeResourceVerification: true,
+		},
+	}
+}
+
+func setupContext(t *testing.T) *locally_context.Context {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(configPath, []byte("name: keyvault-test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &locally_context.Context{
+		Name:                 "keyvault-test",
+		IsValid:              true,
+		RootConfigFilePath:   configPath,
+		EnvironmentVariables: &entities.EnvironmentVariables{},
+	}
+	config := configuration.Get()
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{ctx}
+	config.GlobalConfiguration.CurrentContext = ctx.Name
+
+	return ctx
+}
+
+func TestAzureKeyVault_Sync(t *testing.T) {
+	ctx := setupContext(t)
+	secrets := map[string]string{
+		"api-client-id":     "client",
+		"api-client-secret": base64.StdEncoding.EncodeToString([]byte("s3cr3t")),
+		"portal-key":        "portal",
+	}
+	for i := 0; i < 20; i++ {
+		secrets[fmt.Sprintf("other-%v", i)] = "other"
+	}
+	vault := newFakeKeyVault(t, secrets)
+
+	options := vault.options()
+	options.Prefix = "API-"
+	options.DecodeBase64 = true
+	result, err := New("global", options).Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result) != 2 || result["global.api-client-secret"] != "s3cr3t" || result["global.api-client-id"] != "client" {
+		t.Errorf("unexpected sync result %v", result)
+	}
+	if len(vault.gets) != 2 {
+		t.Errorf("expected only the secrets with the prefix to be read, read %v", vault.gets)
+	}
+	if ctx.EnvironmentVariables.KeyVault["global.api-client-secret"] != "s3cr3t" {
+		t.Errorf("expected the secret to be in the keyvault environment variables")
+	}
+	if environment.Get().GetString("keyvault", "global.api-client-id") != "client" {
+		t.Errorf("expected the secret to be in the keyvault environment vault")
+	}
+
+	vault.gets = nil
+	options = vault.options()
+	options.Secrets = []string{"portal-key"}
+	result, err = New("", options).Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result) != 1 || result["portal-key"] != "portal" || len(vault.gets) != 1 {
+		t.Errorf("expected only the listed secret to be read, got %v", result)
+	}
+
+	result, err = New("", vault.options()).Sync()
+	if err != nil || len(result) != len(secrets) {
+		t.Errorf("expected all the secrets to be synced, got %v %v", len(result), err)
+	}
+
+	options = vault.options()
+	options.Secrets = []string{"missing"}
+	if _, err := New("", options).Sync(); err == nil {
+		t.Errorf("expected a missing secret to fail the sync")
+	}
+}
+
+func TestAzureKeyVault_SetAndDelete(t *testing.T) {
+	ctx := setupContext(t)
+	vault := newFakeKeyVault(t, map[string]string{})
+
+	options := vault.options()
+	options.DecodeBase64 = true
+	kv := New("global", options)
+	if err := kv.SetSecret("client-secret", "s3cr3t"); err != nil {
+		t.Fatalf("SetSecret() error = %v", err)
+	}
+	if vault.secrets["client-secret"] != base64.StdEncoding.EncodeToString([]byte("s3cr3t")) {
+		t.Errorf("expected the secret to be stored base64 encoded, got %q", vault.secrets["client-secret"])
+	}
+	if ctx.EnvironmentVariables.KeyVault["global.client-secret"] != "s3cr3t" {
+		t.Errorf("expected the secret to be added to the keyvault environment variables")
+	}
+
+	if err := kv.DeleteSecret("client-secret"); err != nil {
+		t.Fatalf("DeleteSecret() error = %v", err)
+	}
+	if _, ok := vault.secrets["client-secret"]; ok {
+		t.Errorf("expected the secret to be deleted")
+	}
+	if _, ok := ctx.EnvironmentVariables.KeyVault["global.client-secret"]; ok {
+		t.Errorf("expected the secret to be removed from the keyvault environment variables")
+	}
+
+	if err := kv.DeleteSecret("client-secret"); err != nil {
+		t.Errorf("expected deleting a missing secret to be ignored, got %v", err)
+	}
+}
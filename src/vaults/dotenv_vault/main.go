@@ -0,0 +1,79 @@
+package dotenv_vault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/notifications"
+
+	"github.com/cjlapao/common-go/helper"
+)
+
+// DotEnvVault exposes the values of the .env files declared in the vaults of the context
+// configuration, ${{ dotenv.DATABASE_URL }}
+type DotEnvVault struct {
+	name string
+}
+
+func New() *DotEnvVault {
+	result := DotEnvVault{
+		name: "dotenv",
+	}
+
+	return &result
+}
+
+func (c DotEnvVault) Name() string {
+	return c.name
+}
+
+func (c DotEnvVault) Sync() (map[string]interface{}, error) {
+	config := configuration.Get()
+	context := config.GetCurrentContext()
+	notify := notifications.Get()
+	result := make(map[string]interface{})
+
+	if context == nil {
+		return result, nil
+	}
+	if !context.IsValid {
+		return result, fmt.Errorf("invalid context selected")
+	}
+	if context.Configuration == nil || context.Configuration.Vaults == nil || context.Configuration.Vaults.DotEnv == nil {
+		return result, nil
+	}
+
+	for _, file := range context.Configuration.Vaults.DotEnv.Files {
+		path := file
+		if !filepath.IsAbs(path) && context.RootConfigFilePath != "" {
+			path = filepath.Join(filepath.Dir(context.RootConfigFilePath), path)
+		}
+
+		// files like .env.local are usually not committed so they are optional
+		if !helper.FileExists(path) {
+			notify.Debug("Env file %s was not found, ignoring it", path)
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return result, err
+		}
+
+		values, err := Parse(string(content))
+		if err != nil {
+			return result, fmt.Errorf("there was an error reading the env file %s, %s", path, err.Error())
+		}
+
+		for key, value := range values {
+			formattedKey := strings.ToLower(key)
+			notify.Debug("Synced %s key from %s", formattedKey, path)
+			result[formattedKey] = value
+		}
+	}
+
+	return result, nil
+}
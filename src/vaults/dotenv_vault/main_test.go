@@ -0,0 +1,55 @@
+package dotenv_vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+)
+
+func TestDotEnvVault_Sync(t *testing.T) {
+	folder := t.TempDir()
+	files := map[string]string{
+		"config.yml": "name: dotenv-test\n",
+		".env":       "API_URL=https://api.local\nAPI_TOKEN=shared\n",
+		".env.local": "API_TOKEN=mine\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := configuration.Get()
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{
+		{
+			Name:               "dotenv-test",
+			IsValid:            true,
+			RootConfigFilePath: filepath.Join(folder, "config.yml"),
+			Configuration: &context_entities.ContextConfiguration{
+				Vaults: &context_entities.ContextVaults{
+					DotEnv: &context_entities.DotEnvVault{Files: []string{".env", ".env.local", ".env.missing"}},
+				},
+			},
+		},
+	}
+	config.GlobalConfiguration.CurrentContext = "dotenv-test"
+
+	result, err := New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result["api_url"] != "https://api.local" || result["api_token"] != "mine" {
+		t.Errorf("expected the later files to override the previous ones, got %v", result)
+	}
+
+	if err := os.WriteFile(filepath.Join(folder, ".env.local"), []byte("API_TOKEN\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New().Sync(); err == nil {
+		t.Errorf("expected an invalid file to fail the sync")
+	}
+}
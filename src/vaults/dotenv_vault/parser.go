@@ -0,0 +1,95 @@
+package dotenv_vault
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var keyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// Parse reads the KEY=value pairs of a .env file, lines can start with export, values can be
+// quoted with single quotes, kept as they are, or double quotes, where \n, \t, \" and \\ are
+// escaped, and both can span multiple lines, # starts a comment outside of quotes
+func Parse(content string) (map[string]string, error) {
+	result := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %v is not a KEY=value pair", lineNumber)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if !keyRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %v has an invalid key %s", lineNumber, key)
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			result[key] = unquotedValue(value)
+			continue
+		}
+
+		quote := value[0]
+		value = value[1:]
+		for {
+			end := closingQuote(value, quote)
+			if end >= 0 {
+				if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+					return nil, fmt.Errorf("line %v has text after the closing quote of %s", lineNumber, key)
+				}
+				value = value[:end]
+				break
+			}
+
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("line %v has an unclosed quote in %s", lineNumber, key)
+			}
+			value = value + "\n" + lines[i]
+		}
+
+		if quote == '"' {
+			value = unescape(value)
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func unquotedValue(value string) string {
+	if index := strings.Index(value, " #"); index >= 0 {
+		value = value[:index]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func closingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		if quote == '"' && value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == quote {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,57 @@
+package dotenv_vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# database settings
+DATABASE_HOST=localhost
+export DATABASE_PORT = 5432
+DATABASE_NAME=locally # the default database
+PASSWORD="p@ss # not a comment"
+MESSAGE="first line\nsecond \"line\""
+RAW='no \n escapes'
+CERTIFICATE="-----BEGIN-----
+abc
+-----END-----"
+EMPTY=
+`
+
+	want := map[string]string{
+		"DATABASE_HOST": "localhost",
+		"DATABASE_PORT": "5432",
+		"DATABASE_NAME": "locally",
+		"PASSWORD":      "p@ss # not a comment",
+		"MESSAGE":       "first line\nsecond \"line\"",
+		"RAW":           `no \n escapes`,
+		"CERTIFICATE":   "-----BEGIN-----\nabc\n-----END-----",
+		"EMPTY":         "",
+	}
+
+	got, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing equal":  "DATABASE_HOST",
+		"invalid key":    "DATABASE HOST=localhost",
+		"unclosed quote": "PASSWORD=\"secret\nOTHER=value",
+		"text after":     "PASSWORD=\"secret\" value",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(content); err == nil {
+				t.Errorf("expected %q to fail", content)
+			}
+		})
+	}
+}
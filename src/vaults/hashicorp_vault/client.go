@@ -0,0 +1,208 @@
+package hashicorp_vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// HashiCorpVaultClientOptions sets how the vault api is called, when there is an approle the token
+// is ignored and the client logs in before the first request
+type HashiCorpVaultClientOptions struct {
+	Address   string
+	Namespace string
+	Mount     string
+	Token     string
+	AppRole   *AppRoleOptions
+	Timeout   time.Duration
+	// HttpClient is used instead of the default http client
+	HttpClient *http.Client
+}
+
+type AppRoleOptions struct {
+	Mount    string
+	RoleId   string
+	SecretId string
+}
+
+// HashiCorpVaultClient reads the secrets of a kv v2 engine using the vault http api
+type HashiCorpVaultClient struct {
+	options *HashiCorpVaultClientOptions
+	token   string
+}
+
+type vaultResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Auth   *vaultAuth      `json:"auth"`
+	Errors []string        `json:"errors"`
+}
+
+type vaultAuth struct {
+	ClientToken string `json:"client_token"`
+}
+
+func NewClient(options *HashiCorpVaultClientOptions) (*HashiCorpVaultClient, error) {
+	if options.Address == "" {
+		return nil, errors.New("the vault address cannot be empty")
+	}
+	if options.Mount == "" {
+		options.Mount = "secret"
+	}
+	if options.Timeout == 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.HttpClient == nil {
+		options.HttpClient = &http.Client{}
+	}
+	if options.AppRole != nil {
+		if options.AppRole.RoleId == "" {
+			return nil, errors.New("the approle role id cannot be empty")
+		}
+		if options.AppRole.Mount == "" {
+			options.AppRole.Mount = "approle"
+		}
+	} else if options.Token == "" {
+		return nil, errors.New("a token or an approle is required to read the vault")
+	}
+
+	client := HashiCorpVaultClient{
+		options: options,
+		token:   options.Token,
+	}
+
+	return &client, nil
+}
+
+// Login exchanges the approle credentials for a token, clients using a token do not need to login
+func (client *HashiCorpVaultClient) Login(ctx context.Context) error {
+	if client.options.AppRole == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"role_id":   client.options.AppRole.RoleId,
+		"secret_id": client.options.AppRole.SecretId,
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := client.do(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", trimPath(client.options.AppRole.Mount)), body)
+	if err != nil {
+		return fmt.Errorf("unable to login with the approle, %w", err)
+	}
+	if response.Auth == nil || response.Auth.ClientToken == "" {
+		return errors.New("unable to login with the approle, the vault did not return a token")
+	}
+
+	client.token = response.Auth.ClientToken
+	return nil
+}
+
+// Read returns the fields of the latest version of a secret
+func (client *HashiCorpVaultClient) Read(ctx context.Context, path string) (map[string]interface{}, error) {
+	response, err := client.do(ctx, http.MethodGet, fmt.Sprintf("%s/data/%s", trimPath(client.options.Mount), trimPath(path)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		return nil, err
+	}
+	// deleted versions are returned without data
+	if data.Data == nil {
+		return nil, fmt.Errorf("%w, %s", ErrSecretNotFound, path)
+	}
+
+	return data.Data, nil
+}
+
+// List returns the names of the secrets under a path, folders end with /
+func (client *HashiCorpVaultClient) List(ctx context.Context, path string) ([]string, error) {
+	response, err := client.do(ctx, "LIST", fmt.Sprintf("%s/metadata/%s", trimPath(client.options.Mount), trimPath(path)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Keys []string `json:"keys"`
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		return nil, err
+	}
+
+	return data.Keys, nil
+}
+
+func (client *HashiCorpVaultClient) do(ctx context.Context, method, path string, body []byte) (*vaultResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.options.Timeout)
+	defer cancel()
+
+	endpoint, err := url.JoinPath(client.options.Address, "v1", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = strings.NewReader(string(body))
+	}
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if client.token != "" {
+		request.Header.Set("X-Vault-Token", client.token)
+	}
+	if client.options.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", client.options.Namespace)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := client.options.HttpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result vaultResponse
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &result); err != nil && response.StatusCode < 300 {
+			return nil, fmt.Errorf("the vault returned an invalid response, %s", err.Error())
+		}
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w, %s", ErrSecretNotFound, path)
+	}
+	if response.StatusCode >= 300 {
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("the vault returned %v, %s", response.StatusCode, strings.Join(result.Errors, ", "))
+		}
+		return nil, fmt.Errorf("the vault returned %v", response.StatusCode)
+	}
+
+	return &result, nil
+}
+
+func trimPath(path string) string {
+	return strings.Trim(path, "/")
+}
@@ -0,0 +1,171 @@
+package hashicorp_vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+// HashiCorpVault exposes the kv v2 secrets of the paths declared in the vaults of the context
+// configuration, ${{ hashicorp.database.password }}
+type HashiCorpVault struct {
+	name string
+}
+
+func New() *HashiCorpVault {
+	result := HashiCorpVault{
+		name: "hashicorp",
+	}
+
+	return &result
+}
+
+func (c HashiCorpVault) Name() string {
+	return c.name
+}
+
+func (c HashiCorpVault) Sync() (map[string]interface{}, error) {
+	config := configuration.Get()
+	context := config.GetCurrentContext()
+	notify := notifications.Get()
+	result := make(map[string]interface{})
+
+	if context == nil {
+		return result, nil
+	}
+	if !context.IsValid {
+		return result, fmt.Errorf("invalid context selected")
+	}
+	if context.Configuration == nil || context.Configuration.Vaults == nil || context.Configuration.Vaults.HashiCorp == nil {
+		return result, nil
+	}
+
+	settings := context.Configuration.Vaults.HashiCorp
+	if len(settings.Paths) == 0 {
+		return result, nil
+	}
+
+	// an unreachable vault should not stop the commands that do not need its secrets
+	values, err := read(settings)
+	if err != nil {
+		notify.Warning("HashiCorp vault secrets were not loaded, %s", err.Error())
+		return result, nil
+	}
+
+	for key, value := range values {
+		notify.Debug("Synced %s key from the hashicorp vault", key)
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func read(settings *context_entities.HashiCorpVault) (map[string]interface{}, error) {
+	client, err := NewClient(getClientOptions(settings))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if err := client.Login(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	for _, path := range settings.Paths {
+		prefix := path.Prefix
+		if prefix == "" {
+			prefix = strings.ReplaceAll(trimPath(path.Path), "/", ".")
+		}
+
+		if !strings.HasSuffix(path.Path, "/") {
+			fields, err := client.Read(ctx, path.Path)
+			if err != nil {
+				return nil, err
+			}
+			addFields(result, prefix, fields)
+			continue
+		}
+
+		names, err := client.List(ctx, path.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			// only the secrets directly under the path are read
+			if strings.HasSuffix(name, "/") {
+				continue
+			}
+
+			fields, err := client.Read(ctx, path.Path+name)
+			if err != nil {
+				return nil, err
+			}
+			addFields(result, joinKey(prefix, name), fields)
+		}
+	}
+
+	return result, nil
+}
+
+func getClientOptions(settings *context_entities.HashiCorpVault) *HashiCorpVaultClientOptions {
+	options := HashiCorpVaultClientOptions{
+		Address:   valueOrEnv(settings.Address, "VAULT_ADDR"),
+		Namespace: valueOrEnv(settings.Namespace, "VAULT_NAMESPACE"),
+		Mount:     settings.Mount,
+		Timeout:   time.Duration(settings.Timeout) * time.Second,
+	}
+
+	if settings.AppRole != nil {
+		options.AppRole = &AppRoleOptions{
+			Mount:    settings.AppRole.Mount,
+			RoleId:   settings.AppRole.RoleId,
+			SecretId: valueOrEnv(settings.AppRole.SecretId, "VAULT_SECRET_ID"),
+		}
+	} else {
+		options.Token = valueOrEnv(settings.Token, "VAULT_TOKEN")
+	}
+
+	return &options
+}
+
+// addFields adds the fields of a secret as prefix.field, values that are not strings are kept as
+// their json
+func addFields(result map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for field, value := range fields {
+		key := joinKey(prefix, field)
+		if text, ok := value.(string); ok {
+			result[key] = text
+			continue
+		}
+
+		content, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		result[key] = string(content)
+	}
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return strings.ToLower(name)
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s.%s", prefix, name))
+}
+
+func valueOrEnv(value, variable string) string {
+	if value != "" {
+		return value
+	}
+
+	return os.Getenv(variable)
+}
@@ -0,0 +1,190 @@
+package hashicorp_vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+)
+
+// fakeVault is a local stand-in for the kv v2 and approle apis of a vault server
+type fakeVault struct {
+	server    *httptest.Server
+	secrets   map[string]map[string]interface{}
+	namespace string
+	logins    int
+}
+
+func newFakeVault(t *testing.T, secrets map[string]map[string]interface{}) *fakeVault {
+	vault := &fakeVault{secrets: secrets}
+	vault.server = httptest.NewServer(http.HandlerFunc(vault.handle))
+	t.Cleanup(vault.server.Close)
+
+	return vault
+}
+
+func (vault *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vault.namespace = r.Header.Get("X-Vault-Namespace")
+
+	if r.URL.Path == "/v1/auth/approle/login" && r.Method == http.MethodPost {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"invalid role or secret ID"}})
+			return
+		}
+		vault.logins++
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]string{"client_token": "approle-token"}})
+		return
+	}
+
+	if token := r.Header.Get("X-Vault-Token"); token != "root" && token != "approle-token" {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch {
+	case r.Method == "LIST" && strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/"):
+		folder := strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/") + "/"
+		keys := make([]string, 0)
+		for path := range vault.secrets {
+			if name, ok := strings.CutPrefix(path, folder); ok {
+				if i := strings.Index(name, "/"); i >= 0 {
+					name = name[:i+1]
+				}
+				keys = append(keys, name)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		secret, ok := vault.secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": secret, "metadata": map[string]interface{}{"version": 1}}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func setupContext(t *testing.T, settings *context_entities.HashiCorpVault) {
+	t.Helper()
+	config := configuration.Get()
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{
+		{
+			Name:    "hashicorp-test",
+			IsValid: true,
+			Configuration: &context_entities.ContextConfiguration{
+				Vaults: &context_entities.ContextVaults{HashiCorp: settings},
+			},
+		},
+	}
+	config.GlobalConfiguration.CurrentContext = "hashicorp-test"
+}
+
+func TestHashiCorpVault_Sync(t *testing.T) {
+	vault := newFakeVault(t, map[string]map[string]interface{}{
+		"apps/api":          {"Password": "s3cr3t", "port": 5432},
+		"shared/portal":     {"key": "portal"},
+		"shared/identity":   {"client_secret": "identity"},
+		"shared/nested/one": {"key": "nested"},
+	})
+
+	t.Setenv("VAULT_TOKEN", "root")
+	setupContext(t, &context_entities.HashiCorpVault{
+		Address:   vault.server.URL,
+		Namespace: "team",
+		Paths: []context_entities.HashiCorpVaultPath{
+			{Path: "apps/api", Prefix: "database"},
+			{Path: "shared/"},
+		},
+	})
+
+	result, err := New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"database.password":             "s3cr3t",
+		"database.port":                 "5432",
+		"shared.portal.key":             "portal",
+		"shared.identity.client_secret": "identity",
+	}
+	if len(result) != len(want) {
+		t.Errorf("Sync() = %v, want %v", result, want)
+	}
+	for key, value := range want {
+		if result[key] != value {
+			t.Errorf("Sync()[%s] = %v, want %v", key, result[key], value)
+		}
+	}
+	if vault.namespace != "team" {
+		t.Errorf("expected the namespace to be sent, got %q", vault.namespace)
+	}
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	result, err = New().Sync()
+	if err != nil || len(result) != 0 {
+		t.Errorf("expected a failed read not to fail the sync, got %v %v", result, err)
+	}
+}
+
+func TestHashiCorpVault_AppRole(t *testing.T) {
+	vault := newFakeVault(t, map[string]map[string]interface{}{
+		"apps/api": {"password": "s3cr3t"},
+	})
+
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_SECRET_ID", "secret")
+	settings := &context_entities.HashiCorpVault{
+		Address: vault.server.URL,
+		AppRole: &context_entities.HashiCorpVaultAppRole{RoleId: "role"},
+		Paths:   []context_entities.HashiCorpVaultPath{{Path: "apps/api"}},
+	}
+	setupContext(t, settings)
+
+	result, err := New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result["apps.api.password"] != "s3cr3t" || vault.logins != 1 {
+		t.Errorf("expected the secret to be read after the approle login, got %v", result)
+	}
+
+	settings.AppRole.SecretId = "wrong"
+	client, err := NewClient(getClientOptions(settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Login(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid role or secret ID") {
+		t.Errorf("expected a wrong secret id to fail the login, got %v", err)
+	}
+}
+
+func TestHashiCorpVaultClient_Read(t *testing.T) {
+	vault := newFakeVault(t, map[string]map[string]interface{}{})
+
+	if _, err := NewClient(&HashiCorpVaultClientOptions{Address: vault.server.URL}); err == nil {
+		t.Errorf("expected a client without a token or approle to fail")
+	}
+
+	client, err := NewClient(&HashiCorpVaultClientOptions{Address: vault.server.URL, Token: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Read(context.Background(), "missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected a missing secret to return ErrSecretNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package os_vault
+
+import (
+	"os"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+// OsVault exposes the environment variables of the process, ${{ os.HOME }}, when the context
+// declares a prefix only the variables starting with it are exposed
+type OsVault struct {
+	name string
+}
+
+func New() *OsVault {
+	result := OsVault{
+		name: "os",
+	}
+
+	return &result
+}
+
+func (c OsVault) Name() string {
+	return c.name
+}
+
+func (c OsVault) Sync() (map[string]interface{}, error) {
+	config := configuration.Get()
+	context := config.GetCurrentContext()
+	notify := notifications.Get()
+	result := make(map[string]interface{})
+
+	prefix := ""
+	if context != nil && context.Configuration != nil && context.Configuration.Vaults != nil && context.Configuration.Vaults.Os != nil {
+		prefix = strings.ToLower(context.Configuration.Vaults.Os.Prefix)
+	}
+
+	for _, variable := range os.Environ() {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+
+		formattedKey := strings.ToLower(parts[0])
+		if !strings.HasPrefix(formattedKey, prefix) {
+			continue
+		}
+
+		notify.Debug("Synced %s key", formattedKey)
+		result[formattedKey] = parts[1]
+	}
+
+	return result, nil
+}
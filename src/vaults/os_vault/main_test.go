@@ -0,0 +1,39 @@
+package os_vault
+
+import (
+	"testing"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+)
+
+func TestOsVault_Sync(t *testing.T) {
+	t.Setenv("LOCALLY_TEST_TEAM", "platform")
+	t.Setenv("OTHER_TEST_TEAM", "other")
+
+	config := configuration.Get()
+	ctx := &locally_context.Context{Name: "os-test", IsValid: true, Configuration: &context_entities.ContextConfiguration{}}
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{ctx}
+	config.GlobalConfiguration.CurrentContext = ctx.Name
+
+	result, err := New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result["locally_test_team"] != "platform" || result["other_test_team"] != "other" {
+		t.Errorf("expected all the environment variables without a prefix")
+	}
+
+	ctx.Configuration.Vaults = &context_entities.ContextVaults{Os: &context_entities.OsVault{Prefix: "LOCALLY_"}}
+	result, err = New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result["locally_test_team"] != "platform" {
+		t.Errorf("expected the variables with the prefix to be kept")
+	}
+	if _, ok := result["other_test_team"]; ok {
+		t.Errorf("expected the variables without the prefix to be filtered out")
+	}
+}
@@ -0,0 +1,98 @@
+package pipeline_vault
+
+import (
+	"strings"
+	"sync"
+)
+
+var globalPipelineVault *PipelineVault
+var globalParametersVault *PipelineVault
+var globalStatusVault *PipelineVault
+
+// PipelineVault keeps values that only exist while a pipeline is running, the outputs published
+// by the pipeline steps are available as ${{ steps.<step>.outputs.<name> }}, the pipeline
+// parameters as ${{ parameters.<name> }} and the outcome of the main jobs, used by the onFailure
+// and finally jobs, as ${{ pipeline.<name> }}
+type PipelineVault struct {
+	name   string
+	mutex  sync.RWMutex
+	values map[string]interface{}
+}
+
+func New() *PipelineVault {
+	globalPipelineVault = newVault("steps")
+	return globalPipelineVault
+}
+
+func Get() *PipelineVault {
+	if globalPipelineVault != nil {
+		return globalPipelineVault
+	}
+
+	return New()
+}
+
+func NewParameters() *PipelineVault {
+	globalParametersVault = newVault("parameters")
+	return globalParametersVault
+}
+
+func GetParameters() *PipelineVault {
+	if globalParametersVault != nil {
+		return globalParametersVault
+	}
+
+	return NewParameters()
+}
+
+func NewStatus() *PipelineVault {
+	globalStatusVault = newVault("pipeline")
+	return globalStatusVault
+}
+
+func GetStatus() *PipelineVault {
+	if globalStatusVault != nil {
+		return globalStatusVault
+	}
+
+	return NewStatus()
+}
+
+func newVault(name string) *PipelineVault {
+	result := PipelineVault{
+		name:   name,
+		values: make(map[string]interface{}),
+	}
+
+	return &result
+}
+
+func (c *PipelineVault) Name() string {
+	return c.name
+}
+
+func (c *PipelineVault) Sync() (map[string]interface{}, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	result := make(map[string]interface{})
+	for key, value := range c.values {
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func (c *PipelineVault) Set(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values[strings.ToLower(key)] = value
+}
+
+func (c *PipelineVault) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values = make(map[string]interface{})
+}
@@ -0,0 +1,61 @@
+package secrets_vault
+
+import (
+	"fmt"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	"github.com/cjlapao/locally-cli/notifications"
+)
+
+// SecretsVault exposes the secrets kept in the encrypted file of the context, ${{ secrets.name }}
+type SecretsVault struct {
+	name string
+}
+
+func New() *SecretsVault {
+	result := SecretsVault{
+		name: "secrets",
+	}
+
+	return &result
+}
+
+func (c SecretsVault) Name() string {
+	return c.name
+}
+
+func (c SecretsVault) Sync() (map[string]interface{}, error) {
+	config := configuration.Get()
+	context := config.GetCurrentContext()
+	notify := notifications.Get()
+	result := make(map[string]interface{})
+
+	if context == nil {
+		return result, nil
+	}
+	if !context.IsValid {
+		return result, fmt.Errorf("invalid context selected")
+	}
+
+	store, err := GetStore()
+	if err != nil {
+		return result, err
+	}
+	if !store.Exists() {
+		return result, nil
+	}
+
+	// a missing passphrase should not stop the commands that do not need the secrets
+	secrets, err := store.Load()
+	if err != nil {
+		notify.Warning("Secrets were not loaded, %s", err.Error())
+		return result, nil
+	}
+
+	for key, value := range secrets {
+		notify.Debug("Synced %s secret", key)
+		result[key] = value
+	}
+
+	return result, nil
+}
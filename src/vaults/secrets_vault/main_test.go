@@ -0,0 +1,66 @@
+package secrets_vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cjlapao/locally-cli/configuration"
+	locally_context "github.com/cjlapao/locally-cli/context"
+	context_entities "github.com/cjlapao/locally-cli/context/entities"
+)
+
+func TestSecretsVault_Sync(t *testing.T) {
+	folder := t.TempDir()
+	if err := os.WriteFile(filepath.Join(folder, "config.yml"), []byte("name: secrets-test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := configuration.Get()
+	config.GlobalConfiguration.Contexts = []*locally_context.Context{
+		{
+			Name:               "secrets-test",
+			IsValid:            true,
+			RootConfigFilePath: filepath.Join(folder, "config.yml"),
+			Configuration: &context_entities.ContextConfiguration{
+				Vaults: &context_entities.ContextVaults{
+					Secrets: &context_entities.SecretsVault{File: "secrets.enc", KeyFile: "keys/secrets.key"},
+				},
+			},
+		},
+	}
+	config.GlobalConfiguration.CurrentContext = "secrets-test"
+	t.Setenv(PASSPHRASE_ENV_VARIABLE, "")
+
+	result, err := New().Sync()
+	if err != nil || len(result) != 0 {
+		t.Fatalf("expected a context without secrets to be empty, got %v %v", result, err)
+	}
+
+	store, err := GetStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.FilePath() != filepath.Join(folder, "secrets.enc") {
+		t.Errorf("expected the secrets file to be relative to the context, got %s", store.FilePath())
+	}
+	if err := store.Set("Api_Token", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = New().Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result["api_token"] != "s3cr3t" {
+		t.Errorf("expected the secret to be synced, got %v", result)
+	}
+
+	if err := os.WriteFile(filepath.Join(folder, "keys", "secrets.key"), []byte("bm90IGEga2V5"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result, err = New().Sync()
+	if err != nil || len(result) != 0 {
+		t.Errorf("expected an unreadable secrets file not to fail the sync, got %v %v", result, err)
+	}
+}
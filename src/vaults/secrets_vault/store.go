@@ -0,0 +1,398 @@
+package secrets_vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cjlapao/locally-cli/common"
+	"github.com/cjlapao/locally-cli/configuration"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	PASSPHRASE_ENV_VARIABLE     = "LOCALLY_SECRETS_PASSPHRASE"
+	NEW_PASSPHRASE_ENV_VARIABLE = "LOCALLY_SECRETS_NEW_PASSPHRASE"
+	FILE_VERSION                = 1
+	KEY_SIZE                    = 32
+)
+
+const (
+	KeyModePassphrase = "passphrase"
+	KeyModeKeyFile    = "keyfile"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+var secretNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_.\-]*$`)
+
+// SecretsStoreOptions sets where the secrets are stored and how they are encrypted, when the
+// passphrase is empty the key is read from the key file, which is created on the first write
+type SecretsStoreOptions struct {
+	FilePath    string
+	KeyFilePath string
+	Passphrase  string
+}
+
+// SecretsStore keeps the secrets of a context in a file encrypted with AES-GCM, the key either
+// comes from a passphrase, stretched with scrypt, or from a random key in a key file
+type SecretsStore struct {
+	options *SecretsStoreOptions
+}
+
+// secretsFile is the content of the encrypted file, the secrets are a json object in the data
+type secretsFile struct {
+	Version int    `json:"version"`
+	Mode    string `json:"mode"`
+	Salt    string `json:"salt,omitempty"`
+	Nonce   string `json:"nonce"`
+	Data    string `json:"data"`
+}
+
+func NewStore(options *SecretsStoreOptions) *SecretsStore {
+	return &SecretsStore{
+		options: options,
+	}
+}
+
+// GetStore returns the store of the current context, the passphrase is read from the
+// LOCALLY_SECRETS_PASSPHRASE environment variable
+func GetStore() (*SecretsStore, error) {
+	context := configuration.Get().GetCurrentContext()
+	if context == nil {
+		return nil, errors.New("no context selected")
+	}
+	if !context.IsValid {
+		return nil, errors.New("invalid context selected")
+	}
+
+	options := SecretsStoreOptions{
+		Passphrase: os.Getenv(PASSPHRASE_ENV_VARIABLE),
+	}
+	if context.Configuration != nil && context.Configuration.Vaults != nil && context.Configuration.Vaults.Secrets != nil {
+		options.FilePath = context.Configuration.Vaults.Secrets.File
+		options.KeyFilePath = context.Configuration.Vaults.Secrets.KeyFile
+	}
+
+	folder := filepath.Dir(context.RootConfigFilePath)
+	if options.FilePath == "" {
+		options.FilePath = filepath.Join(folder, ".secrets", fmt.Sprintf("%s.enc", common.EncodeName(context.Name)))
+	} else if !filepath.IsAbs(options.FilePath) {
+		options.FilePath = filepath.Join(folder, options.FilePath)
+	}
+
+	if options.KeyFilePath == "" {
+		configFolder, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		options.KeyFilePath = filepath.Join(configFolder, "locally", "keys", fmt.Sprintf("%s.key", common.EncodeName(context.Name)))
+	} else if !filepath.IsAbs(options.KeyFilePath) {
+		options.KeyFilePath = filepath.Join(folder, options.KeyFilePath)
+	}
+
+	return NewStore(&options), nil
+}
+
+func (store *SecretsStore) FilePath() string {
+	return store.options.FilePath
+}
+
+func (store *SecretsStore) Exists() bool {
+	_, err := os.Stat(store.options.FilePath)
+	return err == nil
+}
+
+// Load decrypts all the secrets, a store without a file has no secrets
+func (store *SecretsStore) Load() (map[string]string, error) {
+	secrets := make(map[string]string)
+	content, err := os.ReadFile(store.options.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return secrets, nil
+		}
+		return nil, err
+	}
+
+	var file secretsFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("the secrets file %s is not valid, %s", store.options.FilePath, err.Error())
+	}
+	if file.Version != FILE_VERSION {
+		return nil, fmt.Errorf("the secrets file %s has the unsupported version %v", store.options.FilePath, file.Version)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(file.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	switch file.Mode {
+	case KeyModePassphrase:
+		if store.options.Passphrase == "" {
+			return nil, fmt.Errorf("the secrets are encrypted with a passphrase, set it in the %s environment variable", PASSPHRASE_ENV_VARIABLE)
+		}
+		if key, err = deriveKey(store.options.Passphrase, salt); err != nil {
+			return nil, err
+		}
+	case KeyModeKeyFile:
+		if key, err = store.readKeyFile(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("the secrets file %s has the unknown key mode %s", store.options.FilePath, file.Mode)
+	}
+
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, nonce, data, []byte(file.Mode))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the secrets file %s, the %s is not the one used to encrypt it", store.options.FilePath, keyDescription(file.Mode))
+	}
+
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// Save encrypts the secrets with the passphrase, or with the key file when there is no passphrase
+func (store *SecretsStore) Save(secrets map[string]string) error {
+	if store.options.Passphrase != "" {
+		return store.write(secrets, KeyModePassphrase, nil)
+	}
+
+	key, err := store.readKeyFile()
+	if errors.Is(err, os.ErrNotExist) {
+		if key, err = newKey(); err != nil {
+			return err
+		}
+		if err := writeFile(store.options.KeyFilePath, []byte(base64.StdEncoding.EncodeToString(key))); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return store.write(secrets, KeyModeKeyFile, key)
+}
+
+func (store *SecretsStore) Get(name string) (string, error) {
+	secrets, err := store.Load()
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("%w, %s", ErrSecretNotFound, name)
+	}
+
+	return value, nil
+}
+
+func (store *SecretsStore) Set(name, value string) error {
+	name = strings.ToLower(name)
+	if !secretNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid secret name %s, it can only have letters, numbers, _, . and -", name)
+	}
+
+	secrets, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	secrets[name] = value
+	return store.Save(secrets)
+}
+
+func (store *SecretsStore) Remove(name string) error {
+	secrets, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	name = strings.ToLower(name)
+	if _, ok := secrets[name]; !ok {
+		return fmt.Errorf("%w, %s", ErrSecretNotFound, name)
+	}
+
+	delete(secrets, name)
+	return store.Save(secrets)
+}
+
+// List returns the sorted names of the secrets
+func (store *SecretsStore) List() ([]string, error) {
+	secrets, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Rotate encrypts the secrets again with the new passphrase or, without one, with a new random key
+// that replaces the key file
+func (store *SecretsStore) Rotate(newPassphrase string) error {
+	secrets, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if newPassphrase != "" {
+		store.options.Passphrase = newPassphrase
+		return store.write(secrets, KeyModePassphrase, nil)
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return err
+	}
+
+	// the new key is only put in place once the secrets are encrypted with it
+	newKeyFile := store.options.KeyFilePath + ".new"
+	if err := writeFile(newKeyFile, []byte(base64.StdEncoding.EncodeToString(key))); err != nil {
+		return err
+	}
+	if err := store.write(secrets, KeyModeKeyFile, key); err != nil {
+		os.Remove(newKeyFile)
+		return err
+	}
+
+	store.options.Passphrase = ""
+	return os.Rename(newKeyFile, store.options.KeyFilePath)
+}
+
+func (store *SecretsStore) write(secrets map[string]string, mode string, key []byte) error {
+	file := secretsFile{
+		Version: FILE_VERSION,
+		Mode:    mode,
+	}
+
+	if mode == KeyModePassphrase {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		derived, err := deriveKey(store.options.Passphrase, salt)
+		if err != nil {
+			return err
+		}
+		key = derived
+		file.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newCipher(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	file.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	file.Data = base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plain, []byte(mode)))
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(store.options.FilePath, content)
+}
+
+func (store *SecretsStore) readKeyFile() ([]byte, error) {
+	content, err := os.ReadFile(store.options.KeyFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("the key file %s was not found, %w", store.options.KeyFilePath, os.ErrNotExist)
+		}
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != KEY_SIZE {
+		return nil, fmt.Errorf("the key file %s is not a valid key", store.options.KeyFilePath)
+	}
+
+	return key, nil
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, KEY_SIZE)
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, KEY_SIZE)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func keyDescription(mode string) string {
+	if mode == KeyModePassphrase {
+		return "passphrase"
+	}
+
+	return "key file"
+}
+
+// writeFile replaces the file in one step so a failed write does not leave half of it behind,
+// only the current user can read it
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, content, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary, path)
+}
@@ -0,0 +1,122 @@
+package secrets_vault
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T, passphrase string) (*SecretsStore, string) {
+	t.Helper()
+	folder := t.TempDir()
+
+	return NewStore(&SecretsStoreOptions{
+		FilePath:    filepath.Join(folder, ".secrets", "test.enc"),
+		KeyFilePath: filepath.Join(folder, "keys", "test.key"),
+		Passphrase:  passphrase,
+	}), folder
+}
+
+func TestSecretsStore_KeyFile(t *testing.T) {
+	store, folder := newTestStore(t, "")
+
+	if names, err := store.List(); err != nil || len(names) != 0 {
+		t.Fatalf("expected a store without a file to be empty, got %v %v", names, err)
+	}
+	if err := store.Set("Api-Token", "s3cr3t"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("db.password", "p@ss"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("not valid", "value"); err == nil {
+		t.Errorf("expected a name with spaces to be rejected")
+	}
+
+	content, err := os.ReadFile(store.FilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "s3cr3t") {
+		t.Errorf("expected the secrets file to be encrypted")
+	}
+	if info, err := os.Stat(filepath.Join(folder, "keys", "test.key")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the key file to be created only readable by the user, got %v", err)
+	}
+
+	value, err := NewStore(store.options).Get("api-token")
+	if err != nil || value != "s3cr3t" {
+		t.Errorf("Get() = %q %v, want s3cr3t", value, err)
+	}
+	names, _ := store.List()
+	if strings.Join(names, ",") != "api-token,db.password" {
+		t.Errorf("List() = %v", names)
+	}
+
+	if err := store.Remove("db.password"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := store.Get("db.password"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected the removed secret not to be found, got %v", err)
+	}
+	if err := store.Remove("db.password"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected removing a missing secret to fail, got %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(folder, "keys", "test.key")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(); err == nil || !strings.Contains(err.Error(), "key file") {
+		t.Errorf("expected a missing key file to fail, got %v", err)
+	}
+}
+
+func TestSecretsStore_Passphrase(t *testing.T) {
+	store, _ := newTestStore(t, "correct horse")
+	if err := store.Set("token", "s3cr3t"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	wrong := NewStore(&SecretsStoreOptions{FilePath: store.FilePath(), Passphrase: "wrong horse"})
+	if _, err := wrong.Load(); err == nil || !strings.Contains(err.Error(), "passphrase is not the one") {
+		t.Errorf("expected a wrong passphrase to fail, got %v", err)
+	}
+
+	missing := NewStore(&SecretsStoreOptions{FilePath: store.FilePath()})
+	if _, err := missing.Load(); err == nil || !strings.Contains(err.Error(), PASSPHRASE_ENV_VARIABLE) {
+		t.Errorf("expected a missing passphrase to fail, got %v", err)
+	}
+}
+
+func TestSecretsStore_Rotate(t *testing.T) {
+	store, _ := newTestStore(t, "")
+	if err := store.Set("token", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	oldKey, _ := os.ReadFile(store.options.KeyFilePath)
+
+	if err := store.Rotate(""); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	newKey, _ := os.ReadFile(store.options.KeyFilePath)
+	if string(oldKey) == string(newKey) {
+		t.Errorf("expected the key file to be replaced")
+	}
+	if value, err := store.Get("token"); err != nil || value != "s3cr3t" {
+		t.Errorf("expected the secrets to be readable with the new key, got %q %v", value, err)
+	}
+
+	if err := store.Rotate("new passphrase"); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	rotated := NewStore(&SecretsStoreOptions{FilePath: store.FilePath(), Passphrase: "new passphrase"})
+	if value, err := rotated.Get("token"); err != nil || value != "s3cr3t" {
+		t.Errorf("expected the secrets to be readable with the new passphrase, got %q %v", value, err)
+	}
+	keyFile := NewStore(&SecretsStoreOptions{FilePath: store.FilePath(), KeyFilePath: store.options.KeyFilePath})
+	if _, err := keyFile.Load(); err == nil {
+		t.Errorf("expected the key file not to be used after rotating to a passphrase")
+	}
+}